@@ -9,13 +9,9 @@ import (
 	"log"
 	"net"
 	"net/http"
-	"os"
-	"os/signal"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
-	sdkresource "go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 const (
@@ -39,24 +35,18 @@ func main() {
 }
 
 func run() (err error) {
-	// Handle SIGINT (CTRL+C) gracefully.
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	resources, err := sdkresource.New(ctx,
-		sdkresource.WithAttributes(
-			semconv.ServiceNameKey.String(serviceName),
-			semconv.ServiceVersionKey.String(serviceVersion),
-			semconv.DeploymentEnvironment(deploymentEnvironment)),
-		sdkresource.WithSchemaURL(semconv.SchemaURL),
-		sdkresource.WithFromEnv(), // pull attributes from OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME environment variables
-		sdkresource.WithProcess(), // This option configures a set of Detectors that discover process information
-		sdkresource.WithOS(),      // This option configures a set of Detectors that discover OS information
-		//sdkresource.WithContainer(), // This option configures a set of Detectors that discover container information
-		sdkresource.WithHost(), // This option configures a set of Detectors that discover host information
+	resources, err := telemetry.NewResource(ctx,
+		telemetry.WithServiceName(serviceName),
+		telemetry.WithServiceVersion(serviceVersion),
+		telemetry.WithDeploymentEnvironment(deploymentEnvironment),
+		// Prefer the module version and commit stamped by the Go toolchain when available.
+		telemetry.WithBuildInfo(),
 	)
 	if err != nil {
-		fmt.Printf("failed to create resource: %w", err)
+		fmt.Printf("failed to create resource: %v", err)
 		return err
 	}
 
@@ -69,6 +59,8 @@ func run() (err error) {
 	defer func() {
 		err = errors.Join(err, otelShutdown(context.Background()))
 	}()
+	// Record a crash as a span before the process dies.
+	defer telemetry.InstallPanicHandler()
 
 	// Start HTTP server.
 	srv := &http.Server{
@@ -81,21 +73,19 @@ func run() (err error) {
 	srvErr := make(chan error, 1)
 	go func() {
 		srvErr <- srv.ListenAndServe()
+		// Stop waiting for a signal if the server fails to start.
+		cancel()
 	}()
 
-	// Wait for interruption.
-	select {
-	case err = <-srvErr:
+	// Handle SIGINT (CTRL+C) and SIGTERM gracefully: stop the server, then flush telemetry.
+	// When Shutdown is called, ListenAndServe immediately returns ErrServerClosed.
+	err = telemetry.RunWithSignals(ctx, func(ctx context.Context) error {
+		return errors.Join(srv.Shutdown(ctx), otelShutdown(ctx))
+	})
+	if e := <-srvErr; !errors.Is(e, http.ErrServerClosed) {
 		// Error when starting HTTP server.
-		return
-	case <-ctx.Done():
-		// Wait for first CTRL+C.
-		// Stop receiving signal notifications as soon as possible.
-		stop()
+		err = errors.Join(err, e)
 	}
-
-	// When Shutdown is called, ListenAndServe immediately returns ErrServerClosed.
-	err = srv.Shutdown(context.Background())
 	return
 }
 
@@ -114,7 +104,13 @@ func newHTTPHandler() http.Handler {
 	handleFunc("/rolldice/", app.Rolldice)
 	handleFunc("/rolldice/{player}", app.Rolldice)
 
-	// Add HTTP instrumentation for the whole server.
-	handler := otelhttp.NewHandler(mux, "/")
+	// Report the health of the telemetry pipeline for readiness probes.
+	mux.Handle("/healthz", telemetry.HealthHandler())
+	// Report the sampler and its decision counts.
+	mux.Handle("/debug/sampling", telemetry.SamplerDebugHandler())
+
+	// Add HTTP instrumentation for the whole server, naming spans after the matched route and
+	// recording handler panics on the request span.
+	handler := telemetry.NewHTTPHandler(telemetry.RecoveryHandler(mux))
 	return handler
 }
@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+)
+
+// tokenFunc returns the bearer token to send with the next export.
+type tokenFunc func(ctx context.Context) (string, error)
+
+// fetch calls fn and reports failures to the global error handler.
+func (fn tokenFunc) fetch(ctx context.Context) (string, error) {
+	token, err := fn(ctx)
+	if err != nil {
+		err = fmt.Errorf("telemetry: fetching OTLP token: %w", err)
+		otel.Handle(err)
+		return "", err
+	}
+	return token, nil
+}
+
+// tokenCredentials is a gRPC PerRPCCredentials sending a fresh bearer token
+// with every export.
+type tokenCredentials struct {
+	fn     tokenFunc
+	secure bool
+}
+
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.fn.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+// tokenTransport is an http.RoundTripper sending a fresh bearer token with
+// every request of the OTLP/HTTP exporters.
+type tokenTransport struct {
+	fn   tokenFunc
+	base http.RoundTripper
+}
+
+func (t tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.fn.fetch(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	// A RoundTripper must not modify the request it is given.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestTokenProviderHTTP(t *testing.T) {
+	c := newCollector(t)
+	var n atomic.Int64
+	tokens := func(context.Context) (string, error) {
+		return fmt.Sprintf("token-%d", n.Add(1)), nil
+	}
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+		WithTokenProvider(tokens),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(ctx)
+
+	tracer := p.TracerProvider.Tracer("test")
+	for range 2 {
+		_, span := tracer.Start(ctx, "export")
+		span.End()
+	}
+
+	got := c.headers("/v1/traces", "Authorization")
+	want := []string{"Bearer token-1", "Bearer token-2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Authorization headers = %q, want %q", got, want)
+	}
+}
+
+func TestTokenProviderRequiresTLS(t *testing.T) {
+	tokens := func(context.Context) (string, error) { return "secret", nil }
+	for _, exporterType := range []ExporterType{GrpcExporter, HttpExporter} {
+		_, err := Setup(context.Background(), exporterType, "localhost:4317", resource.Empty(),
+			WithoutGlobalRegistration(),
+			WithTokenProvider(tokens),
+		)
+		if err == nil || !strings.Contains(err.Error(), "token provider requires TLS") {
+			t.Errorf("exporter %v: Setup error = %v, want the TLS error", exporterNames[exporterType], err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBaggage(t *testing.T) {
+	ctx := SetBaggage(context.Background(), "tenant_id", "acme")
+	ctx = SetBaggage(ctx, "region", "eu west")
+	if got := GetBaggage(ctx, "tenant_id"); got != "acme" {
+		t.Errorf("tenant_id = %q, want acme", got)
+	}
+	if got := GetBaggage(ctx, "region"); got != "eu west" {
+		t.Errorf("region = %q, want %q", got, "eu west")
+	}
+	if got := GetBaggage(ctx, "missing"); got != "" {
+		t.Errorf("missing = %q, want empty", got)
+	}
+}
+
+func TestBaggageInvalidKey(t *testing.T) {
+	ctx := context.Background()
+	// Baggage keys are any non-empty UTF-8 string.
+	for _, key := range []string{"", "tenant\xff"} {
+		if got := SetBaggage(ctx, key, "acme"); got != ctx {
+			t.Errorf("SetBaggage(%q) changed the context", key)
+		}
+		if got := GetBaggage(ctx, key); got != "" {
+			t.Errorf("GetBaggage(%q) = %q, want empty", key, got)
+		}
+	}
+}
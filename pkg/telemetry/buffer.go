@@ -0,0 +1,175 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// BufferedExporter is a SpanExporter that queues spans in a bounded in-memory
+// buffer and hands them to the wrapped exporter in the background.
+// When the buffer is full, or the wrapped exporter returns an error, the spans
+// are written to a fallback writer so they are never silently lost.
+type BufferedExporter struct {
+	next     sdktrace.SpanExporter
+	fallback sdktrace.SpanExporter
+	size     int
+	// timeout bounds the exports started in the background.
+	timeout time.Duration
+
+	mu      sync.Mutex
+	pending []sdktrace.ReadOnlySpan
+	// exportMu serializes the drains, so that ForceFlush returns only once
+	// the spans taken by a background drain are exported too.
+	exportMu sync.Mutex
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	dropped    atomic.Int64
+	droppedCnt *selfCounter
+
+	shutdownOnce sync.Once
+}
+
+var _ sdktrace.SpanExporter = (*BufferedExporter)(nil)
+
+// NewBufferedExporter returns a BufferedExporter that buffers up to size spans
+// for next and writes overflowing spans to fallback as JSON. The spans written
+// to fallback are counted by the otelcore.span_buffer.overflow counter of the
+// global meter provider.
+func NewBufferedExporter(next sdktrace.SpanExporter, size int, fallback io.Writer) (*BufferedExporter, error) {
+	overflow := newOverflowCounter(&config{})
+	if err := overflow.bind(otel.Meter(name)); err != nil {
+		return nil, err
+	}
+	return newBufferedExporter(next, size, fallback, overflow)
+}
+
+// newOverflowCounter returns the otelcore.span_buffer.overflow counter of cfg.
+func newOverflowCounter(cfg *config) *selfCounter {
+	return cfg.selfCounter("otelcore.span_buffer.overflow",
+		"The number of spans written to the fallback writer instead of the exporter", "{span}")
+}
+
+// newBufferedExporter is like NewBufferedExporter, counting the spans written
+// to fallback with overflow.
+func newBufferedExporter(next sdktrace.SpanExporter, size int, fallback io.Writer, overflow *selfCounter) (*BufferedExporter, error) {
+	if size <= 0 {
+		return nil, errors.New("telemetry: span buffer size must be positive")
+	}
+
+	fallbackExporter, err := stdouttrace.New(stdouttrace.WithWriter(fallback))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &BufferedExporter{
+		next:       next,
+		fallback:   fallbackExporter,
+		size:       size,
+		timeout:    time.Duration(sdktrace.DefaultExportTimeout) * time.Millisecond,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		droppedCnt: overflow,
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e, nil
+}
+
+// ExportSpans queues spans for export without blocking on the wrapped exporter.
+func (e *BufferedExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	room := e.size - len(e.pending)
+	if room > len(spans) {
+		room = len(spans)
+	}
+	e.pending = append(e.pending, spans[:room]...)
+	overflow := spans[room:]
+	e.mu.Unlock()
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+
+	if len(overflow) > 0 {
+		return e.spill(ctx, overflow)
+	}
+	return nil
+}
+
+// Dropped returns the number of spans that were written to the fallback writer.
+func (e *BufferedExporter) Dropped() int64 {
+	return e.dropped.Load()
+}
+
+// ForceFlush exports the buffered spans with the wrapped exporter, spilling
+// them to the fallback writer when that fails.
+func (e *BufferedExporter) ForceFlush(ctx context.Context) error {
+	e.drain(ctx)
+	return ctx.Err()
+}
+
+// Shutdown exports any buffered spans and shuts down the wrapped exporter.
+func (e *BufferedExporter) Shutdown(ctx context.Context) error {
+	var err error
+	e.shutdownOnce.Do(func() {
+		close(e.done)
+		e.wg.Wait()
+		e.drain(ctx)
+		err = errors.Join(e.next.Shutdown(ctx), e.fallback.Shutdown(ctx))
+	})
+	return err
+}
+
+func (e *BufferedExporter) run() {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.wake:
+			ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+			e.drain(ctx)
+			cancel()
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// drain hands all buffered spans to the wrapped exporter.
+func (e *BufferedExporter) drain(ctx context.Context) {
+	e.exportMu.Lock()
+	defer e.exportMu.Unlock()
+
+	e.mu.Lock()
+	spans := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+	if err := e.next.ExportSpans(ctx, spans); err != nil {
+		otel.Handle(err)
+		if err := e.spill(ctx, spans); err != nil {
+			otel.Handle(err)
+		}
+	}
+}
+
+// spill writes spans to the fallback writer and counts them as dropped.
+func (e *BufferedExporter) spill(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.dropped.Add(int64(len(spans)))
+	e.droppedCnt.Add(ctx, int64(len(spans)))
+	return e.fallback.ExportSpans(ctx, spans)
+}
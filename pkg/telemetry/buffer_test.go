@@ -0,0 +1,135 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func testSpans(names ...string) []sdktrace.ReadOnlySpan {
+	stubs := make(tracetest.SpanStubs, len(names))
+	for i, name := range names {
+		stubs[i].Name = name
+	}
+	return stubs.Snapshots()
+}
+
+func TestBufferedExporterSpillsOverflow(t *testing.T) {
+	next := newBlockingSpanExporter()
+	var fallback syncBuffer
+	e, err := NewBufferedExporter(next, 2, &fallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// The first span is taken by the blocked export, leaving room for two.
+	if err := e.ExportSpans(ctx, testSpans("first")); err != nil {
+		t.Fatal(err)
+	}
+	<-next.started
+	if err := e.ExportSpans(ctx, testSpans("a", "b", "overflow")); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+	if !strings.Contains(fallback.String(), `"Name":"overflow"`) {
+		t.Errorf("fallback output %q does not contain the overflowing span", fallback.String())
+	}
+
+	close(next.release)
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(next.Spans()); got != 3 {
+		t.Errorf("exported %d spans, want 3", got)
+	}
+}
+
+func TestBufferedExporterBoundsBackgroundExport(t *testing.T) {
+	recordErrors(t)
+	var fallback syncBuffer
+	e, err := NewBufferedExporter(&hangingSpanExporter{}, 10, &fallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Shutdown(context.Background())
+	e.timeout = 50 * time.Millisecond
+
+	if err := e.ExportSpans(context.Background(), testSpans("stuck")); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for e.Dropped() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the span sent to a hanging exporter was never spilled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestProvidersForceFlushDrainsSpanBuffer(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSpanBuffer(10, &syncBuffer{}),
+	)
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "buffered")
+	span.End()
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(exp.Spans()); got != 1 {
+		t.Errorf("exported %d spans after ForceFlush, want 1", got)
+	}
+}
+
+func TestSpanBufferCountsOverflow(t *testing.T) {
+	recordErrors(t)
+	reader := sdkmetric.NewManualReader()
+	p := setupTest(t,
+		WithCustomTraceExporter(&fakeSpanExporter{err: errors.New("collector down")}),
+		WithCustomMetricReader(reader),
+		WithSpanBuffer(10, &syncBuffer{}),
+	)
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "spilled")
+	span.End()
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	overflow := collectMetric(t, reader, "otelcore.span_buffer.overflow").(metricdata.Sum[int64])
+	if got := overflow.DataPoints[0].Value; got != 1 {
+		t.Errorf("otelcore.span_buffer.overflow = %d, want 1", got)
+	}
+}
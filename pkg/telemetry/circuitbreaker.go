@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// circuitBreakerExporter stops calling the wrapped exporter after threshold
+// consecutive failed exports and drops spans until cooldown has passed.
+// The first export after the cooldown is let through: if it succeeds the
+// breaker closes, otherwise it opens for another cooldown.
+type circuitBreakerExporter struct {
+	sdktrace.SpanExporter
+	threshold int
+	cooldown  time.Duration
+	dropped   *selfCounter
+
+	// now is a variable so that tests can control the clock.
+	now func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// newCircuitBreakerExporter returns a breaker guarding next that counts the
+// spans it drops with the otelcore.circuit_breaker.dropped counter of cfg.
+func newCircuitBreakerExporter(cfg *config, next sdktrace.SpanExporter, threshold int, cooldown time.Duration) *circuitBreakerExporter {
+	return &circuitBreakerExporter{
+		SpanExporter: next,
+		threshold:    threshold,
+		cooldown:     cooldown,
+		dropped: cfg.selfCounter("otelcore.circuit_breaker.dropped",
+			"The number of spans dropped while the exporter circuit breaker was open", "{span}"),
+		now: time.Now,
+	}
+}
+
+// ExportSpans exports spans unless the breaker is open, in which case they
+// are dropped without an error so the batch processor does not report every
+// skipped batch. The health and exporter metrics wrap the exporter inside the
+// breaker, so they keep reporting the failure that opened it.
+func (e *circuitBreakerExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	if e.now().Before(e.openUntil) {
+		e.mu.Unlock()
+		e.dropped.Add(ctx, int64(len(spans)))
+		return nil
+	}
+	e.mu.Unlock()
+
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err == nil {
+		e.failures = 0
+		return nil
+	}
+	e.failures++
+	if e.failures >= e.threshold {
+		e.openUntil = e.now().Add(e.cooldown)
+	}
+	return err
+}
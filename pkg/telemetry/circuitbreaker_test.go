@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	next := &fakeSpanExporter{err: errors.New("collector down")}
+	e := newCircuitBreakerExporter(&config{}, next, 3, time.Minute)
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for range 3 {
+		if err := e.ExportSpans(ctx, nil); err == nil {
+			t.Fatal("export succeeded, want the collector error")
+		}
+	}
+	// The breaker is open: exports are dropped without reaching next.
+	for range 5 {
+		if err := e.ExportSpans(ctx, nil); err != nil {
+			t.Fatalf("export while open returned %v", err)
+		}
+	}
+	if got := next.Calls(); got != 3 {
+		t.Fatalf("exporter called %d times, want 3", got)
+	}
+
+	// After the cooldown one attempt is let through; a failure reopens it.
+	now = now.Add(time.Minute)
+	_ = e.ExportSpans(ctx, nil)
+	_ = e.ExportSpans(ctx, nil)
+	if got := next.Calls(); got != 4 {
+		t.Fatalf("exporter called %d times after the cooldown, want 4", got)
+	}
+
+	// Once the collector is back the breaker closes.
+	next.setErr(nil)
+	now = now.Add(time.Minute)
+	for range 3 {
+		if err := e.ExportSpans(ctx, nil); err != nil {
+			t.Fatalf("export after recovery returned %v", err)
+		}
+	}
+	if got := next.Calls(); got != 7 {
+		t.Errorf("exporter called %d times after recovery, want 7", got)
+	}
+}
+
+func TestCircuitBreakerKeepsHealthUnhealthy(t *testing.T) {
+	exp := &fakeSpanExporter{err: errors.New("collector down")}
+	p := setupGlobalTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithCircuitBreaker(2, time.Hour),
+	)
+
+	tracer := p.TracerProvider.Tracer("test")
+	for range 5 {
+		_, span := tracer.Start(context.Background(), "work")
+		span.End()
+	}
+	if got := exp.Calls(); got != 2 {
+		t.Fatalf("exporter called %d times, want 2", got)
+	}
+
+	rec := httptest.NewRecorder()
+	HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("health status = %d, want 503", rec.Code)
+	}
+	var body struct {
+		Signals map[string]signalStatus `json:"signals"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if got := body.Signals["traces"].Status; got != "unhealthy" {
+		t.Errorf("traces status = %q, want unhealthy", got)
+	}
+}
+
+func TestCircuitBreakerCountsDropped(t *testing.T) {
+	recordErrors(t)
+	exp := &fakeSpanExporter{err: errors.New("collector down")}
+	reader := sdkmetric.NewManualReader()
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithCustomMetricReader(reader),
+		WithSyncSpanProcessor(),
+		WithCircuitBreaker(2, time.Hour),
+	)
+
+	tracer := p.TracerProvider.Tracer("test")
+	for range 5 {
+		_, span := tracer.Start(context.Background(), "work")
+		span.End()
+	}
+
+	dropped := collectMetric(t, reader, "otelcore.circuit_breaker.dropped").(metricdata.Sum[int64])
+	if got := dropped.DataPoints[0].Value; got != 3 {
+		t.Errorf("otelcore.circuit_breaker.dropped = %d, want 3", got)
+	}
+}
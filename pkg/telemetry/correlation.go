@@ -0,0 +1,58 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogFields returns the trace_id and span_id of the span in ctx as key/value
+// pairs for structured loggers, or nil when ctx has no valid span.
+func LogFields(ctx context.Context) []any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []any{"trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()}
+}
+
+// correlatingWriter prefixes every line with the trace and span IDs of ctx.
+type correlatingWriter struct {
+	w      io.Writer
+	prefix []byte
+}
+
+// NewCorrelatingWriter returns a writer prefixing every line written to w with
+// the trace_id and span_id of the span in ctx, for loggers of the standard log
+// package that are created per request:
+//
+//	logger := log.New(telemetry.NewCorrelatingWriter(ctx, os.Stderr), "", log.LstdFlags)
+//
+// It returns w itself when ctx has no valid span.
+func NewCorrelatingWriter(ctx context.Context, w io.Writer) io.Writer {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return w
+	}
+	prefix := "trace_id=" + sc.TraceID().String() + " span_id=" + sc.SpanID().String() + " "
+	return &correlatingWriter{w: w, prefix: []byte(prefix)}
+}
+
+func (c *correlatingWriter) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+	for rest := p; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i+1]
+		}
+		buf.Write(c.prefix)
+		buf.Write(line)
+		rest = rest[len(line):]
+	}
+	if _, err := c.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
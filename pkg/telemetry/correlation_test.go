@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestCorrelatingWriter(t *testing.T) {
+	p := setupTest(t)
+	ctx, span := p.TracerProvider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	var buf bytes.Buffer
+	logger := log.New(NewCorrelatingWriter(ctx, &buf), "", 0)
+	logger.Print("charging card")
+	logger.Print("card charged")
+
+	sc := span.SpanContext()
+	prefix := "trace_id=" + sc.TraceID().String() + " span_id=" + sc.SpanID().String() + " "
+	want := prefix + "charging card\n" + prefix + "card charged\n"
+	if got := buf.String(); got != want {
+		t.Errorf("log output = %q, want %q", got, want)
+	}
+}
+
+func TestCorrelatingWriterWithoutSpan(t *testing.T) {
+	var buf bytes.Buffer
+	log.New(NewCorrelatingWriter(context.Background(), &buf), "", 0).Print("startup")
+	if got := buf.String(); strings.Contains(got, "trace_id") {
+		t.Errorf("log output without a span = %q, want no trace ID", got)
+	}
+	if fields := LogFields(context.Background()); fields != nil {
+		t.Errorf("LogFields without a span = %v, want nil", fields)
+	}
+}
@@ -0,0 +1,173 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// streamKey identifies a single timeseries across exports.
+type streamKey struct {
+	scope, metric string
+	attrs         attribute.Distinct
+}
+
+// cumulativeMaxStale is how long a timeseries may go without a data point
+// before its running total is forgotten, like max_stale of the Collector's
+// deltatocumulative processor. A series reported again afterwards restarts.
+const cumulativeMaxStale = 5 * time.Minute
+
+// cumulativeExporter converts delta sums and histograms to cumulative ones
+// before exporting them, keeping a running total for every timeseries.
+// The Go SDK has no deltatocumulative processor like the Collector's, so the
+// conversion is done on the export path. Delta exponential histograms cannot
+// be merged across scale changes and are dropped.
+type cumulativeExporter struct {
+	sdkmetric.Exporter
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    map[streamKey]any
+	seen     map[streamKey]time.Time
+	rejected map[string]struct{}
+}
+
+func newCumulativeExporter(next sdkmetric.Exporter) *cumulativeExporter {
+	return &cumulativeExporter{
+		Exporter: next,
+		now:      time.Now,
+		state:    make(map[streamKey]any),
+		seen:     make(map[streamKey]time.Time),
+		rejected: make(map[string]struct{}),
+	}
+}
+
+func (e *cumulativeExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	e.mu.Lock()
+	now := e.now()
+	out := *rm
+	out.ScopeMetrics = make([]metricdata.ScopeMetrics, len(rm.ScopeMetrics))
+	for i, sm := range rm.ScopeMetrics {
+		metrics := make([]metricdata.Metrics, 0, len(sm.Metrics))
+		for _, m := range sm.Metrics {
+			if isDeltaExponentialHistogram(m.Data) {
+				e.reject(sm.Scope.Name, m.Name)
+				continue
+			}
+			m.Data = e.accumulate(sm.Scope.Name, m.Name, m.Data, now)
+			metrics = append(metrics, m)
+		}
+		out.ScopeMetrics[i] = metricdata.ScopeMetrics{Scope: sm.Scope, Metrics: metrics}
+	}
+	for key, t := range e.seen {
+		if now.Sub(t) > cumulativeMaxStale {
+			delete(e.state, key)
+			delete(e.seen, key)
+		}
+	}
+	e.mu.Unlock()
+
+	return e.Exporter.Export(ctx, &out)
+}
+
+// reject reports the first delta exponential histogram of every metric.
+func (e *cumulativeExporter) reject(scope, metric string) {
+	name := scope + "/" + metric
+	if _, ok := e.rejected[name]; ok {
+		return
+	}
+	e.rejected[name] = struct{}{}
+	otel.Handle(fmt.Errorf("telemetry: dropping metric %q of %q: delta exponential histograms cannot be converted to cumulative", metric, scope))
+}
+
+func isDeltaExponentialHistogram(data metricdata.Aggregation) bool {
+	switch d := data.(type) {
+	case metricdata.ExponentialHistogram[int64]:
+		return d.Temporality == metricdata.DeltaTemporality
+	case metricdata.ExponentialHistogram[float64]:
+		return d.Temporality == metricdata.DeltaTemporality
+	}
+	return false
+}
+
+func (e *cumulativeExporter) accumulate(scope, metric string, data metricdata.Aggregation, now time.Time) metricdata.Aggregation {
+	switch d := data.(type) {
+	case metricdata.Sum[int64]:
+		return accumulateSum(e, scope, metric, d, now)
+	case metricdata.Sum[float64]:
+		return accumulateSum(e, scope, metric, d, now)
+	case metricdata.Histogram[int64]:
+		return accumulateHistogram(e, scope, metric, d, now)
+	case metricdata.Histogram[float64]:
+		return accumulateHistogram(e, scope, metric, d, now)
+	}
+	return data
+}
+
+func accumulateSum[N int64 | float64](e *cumulativeExporter, scope, metric string, s metricdata.Sum[N], now time.Time) metricdata.Sum[N] {
+	if s.Temporality != metricdata.DeltaTemporality {
+		return s
+	}
+	points := make([]metricdata.DataPoint[N], len(s.DataPoints))
+	for i, dp := range s.DataPoints {
+		key := streamKey{scope: scope, metric: metric, attrs: dp.Attributes.Equivalent()}
+		if prev, ok := e.state[key].(metricdata.DataPoint[N]); ok {
+			dp.StartTime = prev.StartTime
+			dp.Value += prev.Value
+		}
+		e.state[key] = dp
+		e.seen[key] = now
+		points[i] = dp
+	}
+	s.DataPoints = points
+	s.Temporality = metricdata.CumulativeTemporality
+	return s
+}
+
+func accumulateHistogram[N int64 | float64](e *cumulativeExporter, scope, metric string, h metricdata.Histogram[N], now time.Time) metricdata.Histogram[N] {
+	if h.Temporality != metricdata.DeltaTemporality {
+		return h
+	}
+	points := make([]metricdata.HistogramDataPoint[N], len(h.DataPoints))
+	for i, dp := range h.DataPoints {
+		key := streamKey{scope: scope, metric: metric, attrs: dp.Attributes.Equivalent()}
+		dp.BucketCounts = slices.Clone(dp.BucketCounts)
+		// A change of boundaries restarts the series.
+		if prev, ok := e.state[key].(metricdata.HistogramDataPoint[N]); ok && slices.Equal(prev.Bounds, dp.Bounds) {
+			dp.StartTime = prev.StartTime
+			dp.Count += prev.Count
+			dp.Sum += prev.Sum
+			for b := range dp.BucketCounts {
+				dp.BucketCounts[b] += prev.BucketCounts[b]
+			}
+			dp.Min = mergeExtrema(prev.Min, dp.Min, func(a, b N) N { return min(a, b) })
+			dp.Max = mergeExtrema(prev.Max, dp.Max, func(a, b N) N { return max(a, b) })
+		}
+		e.state[key] = dp
+		e.seen[key] = now
+		points[i] = dp
+	}
+	h.DataPoints = points
+	h.Temporality = metricdata.CumulativeTemporality
+	return h
+}
+
+func mergeExtrema[N int64 | float64](a, b metricdata.Extrema[N], pick func(N, N) N) metricdata.Extrema[N] {
+	av, aok := a.Value()
+	bv, bok := b.Value()
+	switch {
+	case aok && bok:
+		return metricdata.NewExtrema(pick(av, bv))
+	case aok:
+		return a
+	default:
+		return b
+	}
+}
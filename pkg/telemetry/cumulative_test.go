@@ -0,0 +1,109 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// lastMetricExporter keeps the last exported ResourceMetrics.
+type lastMetricExporter struct {
+	discardMetricExporter
+	last *metricdata.ResourceMetrics
+}
+
+func (e *lastMetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.last = rm
+	return nil
+}
+
+func deltaSum(user string, value int64) *metricdata.ResourceMetrics {
+	return &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{
+		Metrics: []metricdata.Metrics{{
+			Name: "requests",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.DeltaTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{{
+					Attributes: attribute.NewSet(attribute.String("user", user)),
+					Value:      value,
+				}},
+			},
+		}},
+	}}}
+}
+
+func exportedSum(t *testing.T, e *lastMetricExporter) int64 {
+	t.Helper()
+	sum, ok := e.last.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if !ok || sum.Temporality != metricdata.CumulativeTemporality {
+		t.Fatalf("exported %#v, want a cumulative sum", e.last.ScopeMetrics[0].Metrics[0].Data)
+	}
+	return sum.DataPoints[0].Value
+}
+
+func TestCumulativeExporterAccumulatesAndEvicts(t *testing.T) {
+	next := &lastMetricExporter{}
+	e := newCumulativeExporter(next)
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, v := range []int64{2, 3} {
+		if err := e.Export(ctx, deltaSum("a", v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := exportedSum(t, next); got != 5 {
+		t.Errorf("cumulative value = %d, want 5", got)
+	}
+
+	// Series b keeps being reported while a goes stale.
+	for range 2 {
+		now = now.Add(cumulativeMaxStale/2 + time.Second)
+		if err := e.Export(ctx, deltaSum("b", 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(e.state) != 1 {
+		t.Errorf("%d series kept, want only the active one", len(e.state))
+	}
+
+	// A stale series restarts from its next delta.
+	if err := e.Export(ctx, deltaSum("a", 4)); err != nil {
+		t.Fatal(err)
+	}
+	if got := exportedSum(t, next); got != 4 {
+		t.Errorf("cumulative value after eviction = %d, want 4", got)
+	}
+}
+
+func TestCumulativeExporterDropsDeltaExponentialHistograms(t *testing.T) {
+	errs := recordErrors(t)
+	next := &lastMetricExporter{}
+	e := newCumulativeExporter(next)
+	rm := deltaSum("a", 1)
+	rm.ScopeMetrics[0].Metrics = append(rm.ScopeMetrics[0].Metrics, metricdata.Metrics{
+		Name: "latency",
+		Data: metricdata.ExponentialHistogram[float64]{
+			Temporality: metricdata.DeltaTemporality,
+			DataPoints:  []metricdata.ExponentialHistogramDataPoint[float64]{{Count: 1, Scale: 20}},
+		},
+	})
+
+	for range 2 {
+		if err := e.Export(context.Background(), rm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metrics := next.last.ScopeMetrics[0].Metrics
+	if len(metrics) != 1 || metrics[0].Name != "requests" {
+		t.Errorf("exported metrics %v, want only requests", metrics)
+	}
+	if got := len(errs.Errors()); got != 1 {
+		t.Errorf("reported %d errors, want 1", got)
+	}
+}
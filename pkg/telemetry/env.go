@@ -0,0 +1,164 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SetupFromEnv bootstraps the OpenTelemetry pipeline from the standard OTEL_*
+// environment variables. The following variables are supported:
+//
+//   - OTEL_EXPORTER_OTLP_PROTOCOL: grpc, http/protobuf (or http), or none to
+//     discard all telemetry. Defaults to http/protobuf. The Go OTLP exporters
+//     do not implement http/json, so it is reported as unsupported.
+//   - OTEL_EXPORTER_OTLP_ENDPOINT: the collector URL, e.g. http://localhost:4318,
+//     applied with WithEndpointURL.
+//   - OTEL_EXPORTER_OTLP_INSECURE: true or false, applied with WithInsecure.
+//   - OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG.
+//   - OTEL_METRIC_EXPORT_INTERVAL: the metric export interval in milliseconds.
+//   - OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES.
+//   - OTEL_BSP_SCHEDULE_DELAY, OTEL_BSP_EXPORT_TIMEOUT, OTEL_BSP_MAX_QUEUE_SIZE
+//     and OTEL_BSP_MAX_EXPORT_BATCH_SIZE, which every Setup honors.
+//
+// Unknown values are reported as errors. opts are applied after the
+// environment, so they take precedence.
+// If it does not return an error, make sure to call shutdown for proper cleanup.
+func SetupFromEnv(ctx context.Context, opts ...Option) (shutdown func(context.Context) error, err error) {
+	exporterType, err := exporterTypeFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var envOpts []Option
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" {
+		envOpts = append(envOpts, WithEndpointURL(v))
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_INSECURE"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("telemetry: invalid OTEL_EXPORTER_OTLP_INSECURE %q: must be true or false", v)
+		}
+		envOpts = append(envOpts, WithInsecure(insecure))
+	}
+
+	sampler, err := samplerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if sampler != nil {
+		envOpts = append(envOpts, WithSampler(sampler))
+	}
+
+	if v := os.Getenv("OTEL_METRIC_EXPORT_INTERVAL"); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil || ms <= 0 {
+			return nil, fmt.Errorf("telemetry: invalid OTEL_METRIC_EXPORT_INTERVAL %q: must be a positive number of milliseconds", v)
+		}
+		envOpts = append(envOpts, WithMetricExportInterval(time.Duration(ms)*time.Millisecond))
+	}
+
+	res, err := detectResource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return SetupOTelSDK(ctx, exporterType, "", res, append(envOpts, opts...)...)
+}
+
+func exporterTypeFromEnv() (ExporterType, error) {
+	switch v := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); v {
+	case "", "http/protobuf", "http":
+		return HttpExporter, nil
+	case "grpc":
+		return GrpcExporter, nil
+	case "none":
+		return NoneExporter, nil
+	case "http/json":
+		return 0, errors.New("telemetry: unsupported OTEL_EXPORTER_OTLP_PROTOCOL \"http/json\": the Go OTLP exporters only implement grpc and http/protobuf")
+	default:
+		return 0, fmt.Errorf("telemetry: unsupported OTEL_EXPORTER_OTLP_PROTOCOL %q", v)
+	}
+}
+
+// samplerFromEnv returns the sampler described by OTEL_TRACES_SAMPLER, or nil
+// when it is unset.
+func samplerFromEnv() (sdktrace.Sampler, error) {
+	name := os.Getenv("OTEL_TRACES_SAMPLER")
+	arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG")
+
+	ratio := func() (float64, error) {
+		if arg == "" {
+			return 1, nil
+		}
+		r, err := strconv.ParseFloat(arg, 64)
+		if err != nil || r < 0 || r > 1 {
+			return 0, fmt.Errorf("telemetry: invalid OTEL_TRACES_SAMPLER_ARG %q: must be a ratio between 0 and 1", arg)
+		}
+		return r, nil
+	}
+
+	switch name {
+	case "":
+		return nil, nil
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "traceidratio":
+		r, err := ratio()
+		if err != nil {
+			return nil, err
+		}
+		return sdktrace.TraceIDRatioBased(r), nil
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		r, err := ratio()
+		if err != nil {
+			return nil, err
+		}
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(r)), nil
+	default:
+		return nil, fmt.Errorf("telemetry: unsupported OTEL_TRACES_SAMPLER %q", name)
+	}
+}
+
+// spanBatchFromEnv applies the OTEL_BSP_* variables configuring the batch span
+// processor: OTEL_BSP_SCHEDULE_DELAY and OTEL_BSP_EXPORT_TIMEOUT in
+// milliseconds, OTEL_BSP_MAX_QUEUE_SIZE and OTEL_BSP_MAX_EXPORT_BATCH_SIZE.
+// Invalid values are reported by Validate.
+func (c *config) spanBatchFromEnv() {
+	positiveInt := func(key string) (int, bool) {
+		v := os.Getenv(key)
+		if v == "" {
+			return 0, false
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: invalid %s %q: must be a positive integer", key, v))
+			return 0, false
+		}
+		return n, true
+	}
+
+	if ms, ok := positiveInt("OTEL_BSP_SCHEDULE_DELAY"); ok {
+		c.spanScheduleDelay = time.Duration(ms) * time.Millisecond
+	}
+	if ms, ok := positiveInt("OTEL_BSP_EXPORT_TIMEOUT"); ok {
+		c.spanExportTimeout = time.Duration(ms) * time.Millisecond
+	}
+	if n, ok := positiveInt("OTEL_BSP_MAX_QUEUE_SIZE"); ok {
+		c.spanMaxQueueSize = n
+	}
+	if n, ok := positiveInt("OTEL_BSP_MAX_EXPORT_BATCH_SIZE"); ok {
+		c.spanMaxBatchSize = n
+	}
+}
@@ -0,0 +1,151 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestExporterTypeFromEnv(t *testing.T) {
+	tests := []struct {
+		protocol string
+		want     ExporterType
+		err      string
+	}{
+		{"", HttpExporter, ""},
+		{"http/protobuf", HttpExporter, ""},
+		{"http", HttpExporter, ""},
+		{"grpc", GrpcExporter, ""},
+		{"none", NoneExporter, ""},
+		{"http/json", 0, `unsupported OTEL_EXPORTER_OTLP_PROTOCOL "http/json": the Go OTLP exporters only implement grpc and http/protobuf`},
+		{"thrift", 0, `unsupported OTEL_EXPORTER_OTLP_PROTOCOL "thrift"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tt.protocol)
+			got, err := exporterTypeFromEnv()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("exporterTypeFromEnv() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplerFromEnv(t *testing.T) {
+	tests := []struct {
+		sampler, arg string
+		want         string
+		err          string
+	}{
+		{"", "", "", ""},
+		{"always_off", "", "AlwaysOffSampler", ""},
+		{"traceidratio", "0.25", "TraceIDRatioBased{0.25}", ""},
+		{"traceidratio", "", "AlwaysOnSampler", ""},
+		{"parentbased_traceidratio", "0.5", "ParentBased{root:TraceIDRatioBased{0.5}", ""},
+		{"traceidratio", "2", "", `invalid OTEL_TRACES_SAMPLER_ARG "2"`},
+		{"jaeger_remote", "", "", `unsupported OTEL_TRACES_SAMPLER "jaeger_remote"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sampler+"/"+tt.arg, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+			sampler, err := samplerFromEnv()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			if sampler != nil {
+				got = sampler.Description()
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("sampler = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "none")
+	t.Setenv("OTEL_TRACES_SAMPLER", "traceidratio")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0")
+	ctx := context.Background()
+	shutdown, err := SetupFromEnv(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		shutdown(ctx)
+		Reset(ctx)
+	})
+
+	_, span := otel.Tracer("test").Start(ctx, "unsampled")
+	defer span.End()
+	if span.SpanContext().IsSampled() {
+		t.Error("span sampled under a zero ratio")
+	}
+}
+
+func TestSpanBatchFromEnv(t *testing.T) {
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "250")
+	t.Setenv("OTEL_BSP_EXPORT_TIMEOUT", "5000")
+	t.Setenv("OTEL_BSP_MAX_QUEUE_SIZE", "64")
+	t.Setenv("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", "16")
+
+	cfg := newConfig(NoneExporter, "", resource.Empty(), nil)
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.spanScheduleDelay != 250*time.Millisecond {
+		t.Errorf("schedule delay = %v, want 250ms", cfg.spanScheduleDelay)
+	}
+	if cfg.spanExportTimeout != 5*time.Second {
+		t.Errorf("export timeout = %v, want 5s", cfg.spanExportTimeout)
+	}
+	if cfg.spanMaxQueueSize != 64 || cfg.spanMaxBatchSize != 16 {
+		t.Errorf("queue and batch sizes = %d and %d, want 64 and 16", cfg.spanMaxQueueSize, cfg.spanMaxBatchSize)
+	}
+
+	// Options take precedence over the environment.
+	cfg = newConfig(NoneExporter, "", resource.Empty(), []Option{WithSpanMaxQueueSize(8), WithSpanMaxExportBatchSize(4)})
+	if cfg.spanMaxQueueSize != 8 || cfg.spanMaxBatchSize != 4 {
+		t.Errorf("queue and batch sizes with options = %d and %d, want 8 and 4", cfg.spanMaxQueueSize, cfg.spanMaxBatchSize)
+	}
+
+	t.Setenv("OTEL_BSP_MAX_QUEUE_SIZE", "-1")
+	cfg = newConfig(NoneExporter, "", resource.Empty(), nil)
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `invalid OTEL_BSP_MAX_QUEUE_SIZE "-1"`) {
+		t.Errorf("Validate error = %v, want the invalid OTEL_BSP_MAX_QUEUE_SIZE", err)
+	}
+}
+
+func TestSpanBatchFromEnvExports(t *testing.T) {
+	// A batch of two fills the export batch, so it is exported without
+	// waiting for the hour-long schedule delay.
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "3600000")
+	t.Setenv("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", "2")
+	exp := &fakeSpanExporter{}
+	p := setupTest(t, WithCustomTraceExporter(exp))
+	for range 2 {
+		_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "work")
+		span.End()
+	}
+	if !waitFor(func() bool { return len(exp.Spans()) == 2 }) {
+		t.Errorf("exported %d spans, want a batch of 2", len(exp.Spans()))
+	}
+}
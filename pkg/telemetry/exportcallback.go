@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExportStats describes the export of a batch of spans, passed to the
+// callback set with WithExportCallback.
+type ExportStats struct {
+	// Batch is the number of spans in the batch.
+	Batch int
+	// Err is the error the batch failed with, or nil.
+	Err error
+	// Succeeded is the total number of spans exported so far.
+	Succeeded int64
+	// Dropped is the total number of spans whose export failed so far.
+	Dropped int64
+	// Queued is the approximate number of ended spans waiting to be exported.
+	Queued int64
+}
+
+// callbackExporter reports the outcome of every export to fn.
+type callbackExporter struct {
+	sdktrace.SpanExporter
+	fn func(ExportStats)
+	// queued is shared with the queueSpanProcessor of the primary exporter.
+	queued *atomic.Int64
+
+	succeeded, dropped atomic.Int64
+}
+
+func (e *callbackExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	n := int64(len(spans))
+	if err != nil {
+		e.dropped.Add(n)
+	} else {
+		e.succeeded.Add(n)
+	}
+	e.fn(ExportStats{
+		Batch:     len(spans),
+		Err:       err,
+		Succeeded: e.succeeded.Load(),
+		Dropped:   e.dropped.Load(),
+		Queued:    e.queued.Load(),
+	})
+	return err
+}
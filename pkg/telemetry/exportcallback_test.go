@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestExportCallbackCounts(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	var mu sync.Mutex
+	var stats []ExportStats
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithExportCallback(func(s ExportStats) {
+			mu.Lock()
+			stats = append(stats, s)
+			mu.Unlock()
+		}),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+	flush := func(n int) error {
+		for range n {
+			_, span := tracer.Start(context.Background(), "work")
+			span.End()
+		}
+		return p.ForceFlush(context.Background())
+	}
+
+	if err := flush(3); err != nil {
+		t.Fatal(err)
+	}
+	errDown := errors.New("collector down")
+	exp.setErr(errDown)
+	if err := flush(2); !errors.Is(err, errDown) {
+		t.Fatalf("ForceFlush error = %v, want %v", err, errDown)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []ExportStats{
+		{Batch: 3, Succeeded: 3},
+		{Batch: 2, Err: errDown, Succeeded: 3, Dropped: 2},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("callback called with %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Errorf("export %d stats = %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package telemetry
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// version is the version of this module reported in the default user agent.
+const version = "0.1.0"
+
+const defaultUserAgent = "go-otel-core/" + version
+
+// httpHeaders returns the headers sent by the OTLP/HTTP exporters, adding
+// extra to the User-Agent. The exporters replace their headers on every
+// WithHeaders option, so all headers must be passed together.
+func (c *config) httpHeaders(extra map[string]string) map[string]string {
+	headers := make(map[string]string, len(extra)+1)
+	if c.userAgent != "" {
+		headers["User-Agent"] = c.userAgent
+	}
+	for k, v := range extra {
+		headers[k] = v
+	}
+	return headers
+}
+
+// unixSocketPath returns the socket path of an otlpAddress such as
+// unix:///var/run/otel.sock.
+func unixSocketPath(otlpAddress string) (string, bool) {
+	return strings.CutPrefix(otlpAddress, "unix://")
+}
+
+// dialUnix returns a gRPC dialer connecting to the Unix socket at path,
+// whatever the address resolved from the target.
+func dialUnix(path string) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+}
+
+// grpcDialOptions returns the dial options shared by the gRPC exporters.
+// WithDialOption replaces the options of earlier calls, so they must be
+// passed all at once.
+func grpcDialOptions(cfg *config) []grpc.DialOption {
+	var opts []grpc.DialOption
+	if path, ok := unixSocketPath(cfg.otlpAddress); ok {
+		opts = append(opts, grpc.WithContextDialer(dialUnix(path)))
+	} else if cfg.srvService != "" {
+		opts = append(opts, grpc.WithContextDialer(dialSRV(cfg.srvService)))
+	}
+	if cfg.userAgent != "" {
+		opts = append(opts, grpc.WithUserAgent(cfg.userAgent))
+	}
+	if cfg.keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(*cfg.keepalive))
+	}
+	if cfg.tokenProvider != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{cfg.tokenProvider, cfg.transportTLS() != nil}))
+	}
+	return opts
+}
+
+// The helpers below translate the config into options for each OTLP exporter.
+// Settings that apply to every exporter are kept in one place so the three
+// signals always agree.
+
+func traceGRPCOptions(cfg *config) []otlptracegrpc.Option {
+	var opts []otlptracegrpc.Option
+	if cfg.grpcConn != nil {
+		// The connection carries its own address, credentials and dial options.
+		opts = append(opts, otlptracegrpc.WithGRPCConn(cfg.grpcConn))
+	} else {
+		opts = append(opts, traceGRPCDialOptions(cfg)...)
+	}
+	if rc := cfg.retry; rc != nil {
+		opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig(*rc)))
+	}
+	return opts
+}
+
+func traceGRPCDialOptions(cfg *config) []otlptracegrpc.Option {
+	var opts []otlptracegrpc.Option
+	if tlsConfig := cfg.transportTLS(); tlsConfig != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if path, ok := unixSocketPath(cfg.otlpAddress); ok {
+		opts = append(opts, otlptracegrpc.WithEndpoint("passthrough:///"+path))
+	} else if cfg.otlpAddress != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.otlpAddress))
+	}
+	if dialOpts := grpcDialOptions(cfg); len(dialOpts) > 0 {
+		opts = append(opts, otlptracegrpc.WithDialOption(dialOpts...))
+	}
+	return opts
+}
+
+func traceHTTPOptions(cfg *config) []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+	if tlsConfig := cfg.transportTLS(); tlsConfig != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	} else {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if cfg.otlpAddress != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.otlpAddress))
+	}
+	if cfg.userAgent != "" {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.httpHeaders(nil)))
+	}
+	if rc := cfg.retry; rc != nil {
+		opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig(*rc)))
+	}
+	if cfg.tracesURLPath != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(cfg.tracesURLPath))
+	}
+	if client := cfg.httpClient(); client != nil {
+		opts = append(opts, otlptracehttp.WithHTTPClient(client))
+	}
+	return opts
+}
+
+func metricGRPCOptions(cfg *config) []otlpmetricgrpc.Option {
+	var opts []otlpmetricgrpc.Option
+	if cfg.grpcConn != nil {
+		// The connection carries its own address, credentials and dial options.
+		opts = append(opts, otlpmetricgrpc.WithGRPCConn(cfg.grpcConn))
+	} else {
+		opts = append(opts, metricGRPCDialOptions(cfg)...)
+	}
+	if rc := cfg.retry; rc != nil {
+		opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig(*rc)))
+	}
+	if cfg.temporalitySelector != nil {
+		opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(cfg.temporalitySelector))
+	}
+	if cfg.aggregationSelector != nil {
+		opts = append(opts, otlpmetricgrpc.WithAggregationSelector(cfg.aggregationSelector))
+	}
+	return opts
+}
+
+func metricGRPCDialOptions(cfg *config) []otlpmetricgrpc.Option {
+	var opts []otlpmetricgrpc.Option
+	if tlsConfig := cfg.transportTLS(); tlsConfig != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if path, ok := unixSocketPath(cfg.otlpAddress); ok {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint("passthrough:///"+path))
+	} else if cfg.otlpAddress != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.otlpAddress))
+	}
+	if dialOpts := grpcDialOptions(cfg); len(dialOpts) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithDialOption(dialOpts...))
+	}
+	return opts
+}
+
+func metricHTTPOptions(cfg *config) []otlpmetrichttp.Option {
+	var opts []otlpmetrichttp.Option
+	if tlsConfig := cfg.transportTLS(); tlsConfig != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+	} else {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if cfg.otlpAddress != "" {
+		opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.otlpAddress))
+	}
+	if cfg.userAgent != "" {
+		opts = append(opts, otlpmetrichttp.WithHeaders(cfg.httpHeaders(nil)))
+	}
+	if rc := cfg.retry; rc != nil {
+		opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig(*rc)))
+	}
+	if cfg.temporalitySelector != nil {
+		opts = append(opts, otlpmetrichttp.WithTemporalitySelector(cfg.temporalitySelector))
+	}
+	if cfg.aggregationSelector != nil {
+		opts = append(opts, otlpmetrichttp.WithAggregationSelector(cfg.aggregationSelector))
+	}
+	if cfg.metricsURLPath != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(cfg.metricsURLPath))
+	}
+	if client := cfg.httpClient(); client != nil {
+		opts = append(opts, otlpmetrichttp.WithHTTPClient(client))
+	}
+	return opts
+}
+
+func logGRPCOptions(cfg *config) []otlploggrpc.Option {
+	var opts []otlploggrpc.Option
+	if cfg.grpcConn != nil {
+		// The connection carries its own address, credentials and dial options.
+		opts = append(opts, otlploggrpc.WithGRPCConn(cfg.grpcConn))
+	} else {
+		opts = append(opts, logGRPCDialOptions(cfg)...)
+	}
+	if rc := cfg.retry; rc != nil {
+		opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig(*rc)))
+	}
+	return opts
+}
+
+func logGRPCDialOptions(cfg *config) []otlploggrpc.Option {
+	var opts []otlploggrpc.Option
+	if tlsConfig := cfg.transportTLS(); tlsConfig != nil {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if path, ok := unixSocketPath(cfg.otlpAddress); ok {
+		opts = append(opts, otlploggrpc.WithEndpoint("passthrough:///"+path))
+	} else if cfg.otlpAddress != "" {
+		opts = append(opts, otlploggrpc.WithEndpoint(cfg.otlpAddress))
+	}
+	if dialOpts := grpcDialOptions(cfg); len(dialOpts) > 0 {
+		opts = append(opts, otlploggrpc.WithDialOption(dialOpts...))
+	}
+	return opts
+}
+
+func logHTTPOptions(cfg *config) []otlploghttp.Option {
+	var opts []otlploghttp.Option
+	if tlsConfig := cfg.transportTLS(); tlsConfig != nil {
+		opts = append(opts, otlploghttp.WithTLSClientConfig(tlsConfig))
+	} else {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+	if cfg.otlpAddress != "" {
+		opts = append(opts, otlploghttp.WithEndpoint(cfg.otlpAddress))
+	}
+	if cfg.userAgent != "" {
+		opts = append(opts, otlploghttp.WithHeaders(cfg.httpHeaders(nil)))
+	}
+	if rc := cfg.retry; rc != nil {
+		opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig(*rc)))
+	}
+	if cfg.logsURLPath != "" {
+		opts = append(opts, otlploghttp.WithURLPath(cfg.logsURLPath))
+	}
+	if client := cfg.httpClient(); client != nil {
+		opts = append(opts, otlploghttp.WithHTTPClient(client))
+	}
+	return opts
+}
+
+// defaultHTTPTimeout is the export timeout of the OTLP/HTTP exporters, which
+// httpClient must set itself.
+const defaultHTTPTimeout = 10 * time.Second
+
+// httpClient returns the client the OTLP/HTTP exporters send with, or nil to
+// let them build their own. The exporters ignore their TLS and timeout
+// options when given a client, so the one built for a token provider carries
+// these settings too.
+func (c *config) httpClient() *http.Client {
+	if c.tokenProvider == nil {
+		return c.customHTTPClient
+	}
+	if c.customHTTPClient != nil {
+		client := *c.customHTTPClient
+		base := client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		client.Transport = tokenTransport{c.tokenProvider, base}
+		return &client
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = c.transportTLS()
+	return &http.Client{Transport: tokenTransport{c.tokenProvider, transport}, Timeout: defaultHTTPTimeout}
+}
+
+// The discard exporters back NoneExporter: they accept and drop everything.
+
+type discardSpanExporter struct{}
+
+func (discardSpanExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error { return nil }
+func (discardSpanExporter) Shutdown(context.Context) error                             { return nil }
+
+type discardMetricExporter struct{}
+
+func (discardMetricExporter) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(k)
+}
+
+func (discardMetricExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+func (discardMetricExporter) Export(context.Context, *metricdata.ResourceMetrics) error { return nil }
+func (discardMetricExporter) ForceFlush(context.Context) error                          { return nil }
+func (discardMetricExporter) Shutdown(context.Context) error                            { return nil }
+
+type discardLogExporter struct{}
+
+func (discardLogExporter) Export(context.Context, []sdklog.Record) error { return nil }
+func (discardLogExporter) ForceFlush(context.Context) error              { return nil }
+func (discardLogExporter) Shutdown(context.Context) error                { return nil }
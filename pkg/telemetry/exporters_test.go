@@ -0,0 +1,414 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// countingTransport counts the requests it sends with http.DefaultTransport.
+type countingTransport struct {
+	n atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.n.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	c := newCollector(t)
+	transport := &countingTransport{}
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+		WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(ctx)
+
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "export")
+	span.End()
+
+	if got := transport.n.Load(); got != 1 {
+		t.Errorf("custom transport sent %d requests, want 1", got)
+	}
+	if got := len(c.headers("/v1/traces", "Content-Type")); got != 1 {
+		t.Errorf("collector received %d trace requests, want 1", got)
+	}
+}
+
+func TestWithHTTPClientNil(t *testing.T) {
+	_, err := Setup(context.Background(), HttpExporter, "", resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithHTTPClient(nil),
+	)
+	if err == nil || !strings.Contains(err.Error(), "HTTP client must not be nil") {
+		t.Errorf("Setup error = %v, want the nil client error", err)
+	}
+}
+
+// flakyCollector is an OTLP/HTTP server failing the first request to every
+// path with 503 and counting the requests per path.
+type flakyCollector struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func newFlakyCollector(t *testing.T) *flakyCollector {
+	t.Helper()
+	c := &flakyCollector{requests: make(map[string]int)}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		c.requests[r.URL.Path]++
+		n := c.requests[r.URL.Path]
+		c.mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// exportRequests returns the requests per path received by a collector
+// failing every first request, when each signal is exported once with
+// retries enabled or not.
+func exportRequests(t *testing.T, retry bool) map[string]int {
+	t.Helper()
+	c := newFlakyCollector(t)
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, strings.TrimPrefix(c.URL, "http://"), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+		WithRetryConfig(RetryConfig{
+			Enabled:         retry,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+			MaxElapsedTime:  time.Second,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "export")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("export"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+	counter, _ := p.MeterProvider.Meter("test").Int64Counter("exports")
+	counter.Add(ctx, 1)
+	p.Shutdown(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+func TestRetryConfig(t *testing.T) {
+	recordErrors(t)
+	without := exportRequests(t, false)
+	with := exportRequests(t, true)
+	// Every exporter retries the failed first request once, and only then.
+	for _, path := range []string{"/v1/traces", "/v1/metrics", "/v1/logs"} {
+		if with[path] != without[path]+1 {
+			t.Errorf("%s received %d requests with retries and %d without, want one more with retries", path, with[path], without[path])
+		}
+	}
+}
+
+func TestHTTPURLPaths(t *testing.T) {
+	c := newCollector(t)
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+		WithHTTPTracesURLPath("/otlp/traces"),
+		WithHTTPMetricsURLPath("/otlp/metrics"),
+		WithHTTPLogsURLPath("/otlp/logs"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportAll(t, p)
+
+	for _, path := range []string{"/otlp/traces", "/otlp/metrics", "/otlp/logs"} {
+		if len(c.headers(path, "Content-Type")) == 0 {
+			t.Errorf("collector received nothing on %s", path)
+		}
+	}
+	for _, path := range []string{"/v1/traces", "/v1/metrics", "/v1/logs"} {
+		if n := len(c.headers(path, "Content-Type")); n > 0 {
+			t.Errorf("collector received %d requests on the default path %s", n, path)
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	const userAgent = "checkout/1.4.2"
+	t.Run("http", func(t *testing.T) {
+		c := newCollector(t)
+		p, err := Setup(context.Background(), HttpExporter, c.address(), resource.Empty(),
+			WithoutGlobalRegistration(),
+			WithSyncSpanProcessor(),
+			WithInsecure(true),
+			WithUserAgent(userAgent),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exportAll(t, p)
+		for _, path := range []string{"/v1/traces", "/v1/metrics", "/v1/logs"} {
+			got := c.headers(path, "User-Agent")
+			if len(got) == 0 || got[0] != userAgent {
+				t.Errorf("%s User-Agent = %q, want %q", path, got, userAgent)
+			}
+		}
+	})
+	t.Run("grpc", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := newGRPCCollector(t, lis)
+		p, err := Setup(context.Background(), GrpcExporter, lis.Addr().String(), resource.Empty(),
+			WithoutGlobalRegistration(),
+			WithSyncSpanProcessor(),
+			WithInsecure(true),
+			WithUserAgent(userAgent),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exportAll(t, p)
+		// gRPC appends its own version to the User-Agent.
+		for _, signal := range []string{"traces", "metrics", "logs"} {
+			got := c.received(signal)
+			if len(got) == 0 || !strings.HasPrefix(got[0], userAgent+" grpc-go/") {
+				t.Errorf("%s User-Agent = %q, want %q and the gRPC version", signal, got, userAgent)
+			}
+		}
+	})
+}
+
+func TestWithGRPCConn(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newGRPCCollector(t, lis)
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUserAgent("shared-conn"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The address is never dialed: every exporter uses conn.
+	p, err := Setup(context.Background(), GrpcExporter, "unused.invalid:4317", resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithGRPCConn(conn),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportAll(t, p)
+
+	for _, signal := range []string{"traces", "metrics", "logs"} {
+		got := c.received(signal)
+		if len(got) == 0 || !strings.HasPrefix(got[0], "shared-conn ") {
+			t.Errorf("%s received over %q, want the shared connection", signal, got)
+		}
+	}
+	if state := conn.GetState(); state == connectivity.Shutdown {
+		t.Error("shutting down the pipeline closed the connection")
+	}
+}
+
+func TestZipkinExporter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		bodies <- body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	p, err := Setup(ctx, ZipkinExporter, "", resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithZipkinURL(srv.URL+"/api/v2/spans"),
+		WithCustomMetricReader(sdkmetric.NewManualReader()),
+		WithCustomLogExporter(&memLogExporter{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(ctx)
+
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "checkout")
+	span.End()
+
+	var spans []struct {
+		TraceID string `json:"traceId"`
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+	}
+	select {
+	case body := <-bodies:
+		if err := json.Unmarshal(body, &spans); err != nil {
+			t.Fatalf("Zipkin request body %s: %v", body, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no span reached the Zipkin collector")
+	}
+	sc := span.SpanContext()
+	if len(spans) != 1 || spans[0].Name != "checkout" ||
+		spans[0].TraceID != sc.TraceID().String() || spans[0].ID != sc.SpanID().String() {
+		t.Errorf("Zipkin spans = %+v, want checkout with trace %s and id %s", spans, sc.TraceID(), sc.SpanID())
+	}
+}
+
+func TestUnixSocketEndpoint(t *testing.T) {
+	// t.TempDir can exceed the length limit of socket paths.
+	dir, err := os.MkdirTemp("", "otel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "otel.sock")
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+	c := newGRPCCollector(t, lis)
+
+	p, err := Setup(context.Background(), GrpcExporter, "unix://"+path, resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportAll(t, p)
+	for _, signal := range []string{"traces", "metrics", "logs"} {
+		if len(c.received(signal)) == 0 {
+			t.Errorf("no %s were exported over the socket", signal)
+		}
+	}
+}
+
+func TestWithKeepalive(t *testing.T) {
+	cfg := newConfig(GrpcExporter, "collector:4317", resource.Empty(), nil)
+	without := len(grpcDialOptions(cfg))
+	WithKeepalive(30*time.Second, 5*time.Second)(cfg)
+	want := keepalive.ClientParameters{Time: 30 * time.Second, Timeout: 5 * time.Second, PermitWithoutStream: true}
+	if cfg.keepalive == nil || *cfg.keepalive != want {
+		t.Errorf("keepalive = %+v, want %+v", cfg.keepalive, want)
+	}
+	if got := len(grpcDialOptions(cfg)); got != without+1 {
+		t.Errorf("got %d gRPC dial options, want the keepalive one added to %d", got, without)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newGRPCCollector(t, lis)
+	p, err := Setup(context.Background(), GrpcExporter, lis.Addr().String(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+		WithKeepalive(30*time.Second, 5*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportAll(t, p)
+	for _, signal := range []string{"traces", "metrics", "logs"} {
+		if len(c.received(signal)) == 0 {
+			t.Errorf("no %s were exported with keepalive", signal)
+		}
+	}
+}
+
+func TestCustomExportersBypassBuiltIn(t *testing.T) {
+	c := newCollector(t)
+	spans := &fakeSpanExporter{}
+	logs := &memLogExporter{}
+	reader := sdkmetric.NewManualReader()
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithInsecure(true),
+		WithSyncSpanProcessor(),
+		WithCustomTraceExporter(spans),
+		WithCustomLogExporter(logs),
+		WithCustomMetricReader(reader),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "work")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("work"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+	requests, _ := p.MeterProvider.Meter("test").Int64Counter("requests")
+	requests.Add(ctx, 1)
+
+	if got := len(spans.Spans()); got != 1 {
+		t.Errorf("custom trace exporter received %d spans, want 1", got)
+	}
+	if got := len(logs.Records()); got != 1 {
+		t.Errorf("custom log exporter received %d records, want 1", got)
+	}
+	if got := collectMetric(t, reader, "requests").(metricdata.Sum[int64]).DataPoints[0].Value; got != 1 {
+		t.Errorf("custom metric reader collected requests = %d, want 1", got)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/v1/traces", "/v1/metrics", "/v1/logs"} {
+		if got := len(c.headers(path, "Content-Type")); got != 0 {
+			t.Errorf("built-in exporter sent %d requests to %s", got, path)
+		}
+	}
+}
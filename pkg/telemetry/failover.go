@@ -0,0 +1,96 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// failoverAttemptTimeout bounds the export to a single endpoint, so that a
+// collector that hangs does not use up the time left for the others.
+const failoverAttemptTimeout = 10 * time.Second
+
+// failoverExporter sends spans to the active exporter of an ordered list and
+// moves on to the next one when it fails.
+type failoverExporter struct {
+	exporters []sdktrace.SpanExporter
+	timeout   time.Duration
+
+	mu     sync.Mutex
+	active int
+}
+
+// newFailoverSpanExporter returns an exporter sending to primary, failing
+// over to exporters for cfg.failoverEndpoints.
+func newFailoverSpanExporter(ctx context.Context, cfg *config, primary sdktrace.SpanExporter) (sdktrace.SpanExporter, error) {
+	e := &failoverExporter{exporters: []sdktrace.SpanExporter{primary}, timeout: failoverAttemptTimeout}
+	for _, endpoint := range cfg.failoverEndpoints {
+		c := *cfg
+		c.otlpAddress = endpoint
+		c.srvService = ""
+
+		var exp sdktrace.SpanExporter
+		var err error
+		if c.exporterType == HttpExporter {
+			exp, err = otlptracehttp.New(ctx, traceHTTPOptions(&c)...)
+		} else {
+			exp, err = otlptracegrpc.New(ctx, traceGRPCOptions(&c)...)
+		}
+		if err != nil {
+			return nil, errors.Join(err, e.Shutdown(ctx))
+		}
+		e.exporters = append(e.exporters, exp)
+	}
+	return e, nil
+}
+
+// ExportSpans exports spans with the active exporter. When it fails, after
+// its own retries, the following exporters are tried in turn and the first
+// one to succeed becomes the active exporter. Each attempt gets its share of
+// the time left before the deadline of ctx, and at most e.timeout.
+func (e *failoverExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	active := e.active
+	e.mu.Unlock()
+
+	var errs []error
+	for i := range e.exporters {
+		idx := (active + i) % len(e.exporters)
+		err := e.attempt(ctx, e.exporters[idx], spans, len(e.exporters)-i)
+		if err == nil {
+			if idx != active {
+				e.mu.Lock()
+				e.active = idx
+				e.mu.Unlock()
+			}
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// attempt exports spans with exp, leaving time for the left-1 exporters that
+// may be tried after it.
+func (e *failoverExporter) attempt(ctx context.Context, exp sdktrace.SpanExporter, spans []sdktrace.ReadOnlySpan, left int) error {
+	timeout := e.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline)/time.Duration(left))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return exp.ExportSpans(ctx, spans)
+}
+
+func (e *failoverExporter) Shutdown(ctx context.Context) error {
+	var err error
+	for _, exp := range e.exporters {
+		err = errors.Join(err, exp.Shutdown(ctx))
+	}
+	return err
+}
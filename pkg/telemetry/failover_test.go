@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// hangingSpanExporter blocks every export until its context is done.
+type hangingSpanExporter struct {
+	fakeSpanExporter
+}
+
+func (e *hangingSpanExporter) ExportSpans(ctx context.Context, _ []sdktrace.ReadOnlySpan) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestFailoverSwitchesEndpoints(t *testing.T) {
+	primary := &fakeSpanExporter{err: errors.New("primary down")}
+	backup := &fakeSpanExporter{}
+	e := &failoverExporter{exporters: []sdktrace.SpanExporter{primary, backup}, timeout: time.Second}
+	ctx := context.Background()
+
+	if err := e.ExportSpans(ctx, nil); err != nil {
+		t.Fatalf("export with a healthy backup returned %v", err)
+	}
+	// The backup stays active once it took over.
+	if err := e.ExportSpans(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if primary.Calls() != 1 || backup.Calls() != 2 {
+		t.Errorf("primary called %d times and backup %d times, want 1 and 2", primary.Calls(), backup.Calls())
+	}
+
+	// When the backup fails in turn, the primary is tried again.
+	primary.setErr(nil)
+	backup.setErr(errors.New("backup down"))
+	if err := e.ExportSpans(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if primary.Calls() != 2 {
+		t.Errorf("primary called %d times, want 2", primary.Calls())
+	}
+
+	primary.setErr(errors.New("primary down"))
+	if err := e.ExportSpans(ctx, nil); err == nil {
+		t.Error("export with every endpoint down succeeded")
+	}
+}
+
+func TestFailoverBoundsEachAttempt(t *testing.T) {
+	backup := &fakeSpanExporter{}
+	e := &failoverExporter{exporters: []sdktrace.SpanExporter{&hangingSpanExporter{}, backup}, timeout: time.Minute}
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := e.ExportSpans(ctx, nil); err != nil {
+		t.Fatalf("export returned %v, want the backup to succeed", err)
+	}
+	if backup.Calls() != 1 {
+		t.Errorf("backup called %d times, want 1", backup.Calls())
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("the hanging primary took %v, want about half of the deadline", elapsed)
+	}
+}
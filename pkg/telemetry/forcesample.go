@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type forceSampleKey struct{}
+
+// withForceSample returns a copy of ctx asking NewForceSampler to sample the
+// spans started with it.
+func withForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+func forceSampled(ctx context.Context) bool {
+	v, _ := ctx.Value(forceSampleKey{}).(bool)
+	return v
+}
+
+// ForceSampleHeaderMiddleware returns middleware that forces the spans of a
+// request carrying headerName, for example X-Debug-Trace, to be sampled,
+// regardless of the configured ratio. The sampled flag is propagated, so
+// downstream services using parent based samplers keep the trace too.
+// The sampler must be wrapped with NewForceSampler, and the middleware must
+// wrap the HTTP instrumentation so the hint is set before the span starts:
+//
+//	handler = telemetry.ForceSampleHeaderMiddleware("X-Debug-Trace")(otelhttp.NewHandler(mux, "/"))
+func ForceSampleHeaderMiddleware(headerName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(headerName) != "" {
+				r = r.WithContext(withForceSample(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forceSampler samples every span whose start context was marked by
+// ForceSampleHeaderMiddleware and defers to sampler otherwise.
+type forceSampler struct {
+	sampler sdktrace.Sampler
+}
+
+// NewForceSampler returns a Sampler honoring ForceSampleHeaderMiddleware and
+// otherwise sampling like sampler. Pass it to WithSampler.
+func NewForceSampler(sampler sdktrace.Sampler) sdktrace.Sampler {
+	return forceSampler{sampler: sampler}
+}
+
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if forceSampled(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.sampler.ShouldSample(p)
+}
+
+func (s forceSampler) Description() string {
+	return fmt.Sprintf("ForceSampler{%s}", s.sampler.Description())
+}
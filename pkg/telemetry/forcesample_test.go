@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestForceSampleHeader(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithSampler(NewForceSampler(sdktrace.TraceIDRatioBased(0))),
+	)
+	handler := ForceSampleHeaderMiddleware("X-Debug-Trace")(otelhttp.NewHandler(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), "checkout",
+		otelhttp.WithTracerProvider(p.TracerProvider)))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/checkout", nil))
+	if got := len(exp.Spans()); got != 0 {
+		t.Fatalf("exported %d spans without the header, want 0", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+	req.Header.Set("X-Debug-Trace", "true")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	spans := exp.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans with the header, want 1", len(spans))
+	}
+	if !spans[0].SpanContext().IsSampled() {
+		t.Error("the forced span is not sampled")
+	}
+}
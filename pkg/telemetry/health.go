@@ -0,0 +1,131 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// signalHealth records the outcome of the most recent export of one signal.
+type signalHealth struct {
+	mu   sync.Mutex
+	err  error
+	last time.Time
+}
+
+func (h *signalHealth) record(err error) {
+	h.mu.Lock()
+	h.err = err
+	h.last = time.Now()
+	h.mu.Unlock()
+}
+
+// signalStatus is the JSON representation of a signalHealth.
+type signalStatus struct {
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	LastExport *time.Time `json:"last_export,omitempty"`
+}
+
+func (h *signalHealth) status() signalStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := signalStatus{Status: "ok"}
+	if !h.last.IsZero() {
+		last := h.last
+		s.LastExport = &last
+	}
+	if h.err != nil {
+		s.Status = "unhealthy"
+		s.Error = h.err.Error()
+	}
+	return s
+}
+
+// pipelineHealth tracks the export health of every signal of a pipeline.
+type pipelineHealth struct {
+	traces, metrics, logs signalHealth
+}
+
+// currentHealth is the health of the pipeline created by the last Setup.
+var currentHealth atomic.Pointer[pipelineHealth]
+
+// HealthHandler returns an http.Handler reporting whether the most recent
+// export of each signal succeeded. It responds with 200 when every signal is
+// healthy and 503 otherwise, with a JSON body describing each signal.
+// It is suitable as a Kubernetes readiness probe.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Status  string                  `json:"status"`
+			Signals map[string]signalStatus `json:"signals,omitempty"`
+		}{Status: "ok"}
+		code := http.StatusOK
+
+		if h := currentHealth.Load(); h == nil {
+			body.Status = "not configured"
+			code = http.StatusServiceUnavailable
+		} else {
+			body.Signals = map[string]signalStatus{
+				"traces":  h.traces.status(),
+				"metrics": h.metrics.status(),
+				"logs":    h.logs.status(),
+			}
+			for _, s := range body.Signals {
+				if s.Status != "ok" {
+					body.Status = "unhealthy"
+					code = http.StatusServiceUnavailable
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+// healthSpanExporter records the result of every export in h.
+type healthSpanExporter struct {
+	sdktrace.SpanExporter
+	h *signalHealth
+}
+
+func (e healthSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.h.record(err)
+	return err
+}
+
+// healthMetricExporter records the result of every export in h.
+type healthMetricExporter struct {
+	sdkmetric.Exporter
+	h *signalHealth
+}
+
+func (e healthMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.h.record(err)
+	return err
+}
+
+// healthLogExporter records the result of every export in h.
+type healthLogExporter struct {
+	sdklog.Exporter
+	h *signalHealth
+}
+
+func (e healthLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.h.record(err)
+	return err
+}
@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func healthStatus() int {
+	rec := httptest.NewRecorder()
+	HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	return rec.Code
+}
+
+func TestHealthHandler(t *testing.T) {
+	if got := healthStatus(); got != http.StatusServiceUnavailable {
+		t.Errorf("status before Setup = %d, want 503", got)
+	}
+
+	recordErrors(t)
+	exp := &fakeSpanExporter{}
+	p := setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+	tracer := p.TracerProvider.Tracer("test")
+	export := func() {
+		_, span := tracer.Start(context.Background(), "work")
+		span.End()
+	}
+
+	export()
+	if got := healthStatus(); got != http.StatusOK {
+		t.Errorf("status after a successful export = %d, want 200", got)
+	}
+	exp.setErr(errors.New("collector down"))
+	export()
+	if got := healthStatus(); got != http.StatusServiceUnavailable {
+		t.Errorf("status after a failed export = %d, want 503", got)
+	}
+	exp.setErr(nil)
+	export()
+	if got := healthStatus(); got != http.StatusOK {
+		t.Errorf("status after the exporter recovered = %d, want 200", got)
+	}
+}
@@ -0,0 +1,345 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// setupTest builds a pipeline discarding its output and not registered as the
+// global one, so tests can run in parallel, and shuts it down at cleanup.
+func setupTest(t *testing.T, opts ...Option) *Providers {
+	t.Helper()
+	opts = append([]Option{WithoutGlobalRegistration()}, opts...)
+	p, err := Setup(context.Background(), NoneExporter, "", resource.Empty(), opts...)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	})
+	return p
+}
+
+// setupGlobalTest is like setupTest but registers the pipeline as the global
+// one, for helpers reporting on it, and resets the globals at cleanup.
+func setupGlobalTest(t *testing.T, opts ...Option) *Providers {
+	t.Helper()
+	p, err := Setup(context.Background(), NoneExporter, "", resource.Empty(), opts...)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := Reset(context.Background()); err != nil {
+			t.Errorf("Reset: %v", err)
+		}
+	})
+	return p
+}
+
+// fakeSpanExporter keeps the exported spans in memory and fails every export
+// with err when it is set.
+type fakeSpanExporter struct {
+	mu       sync.Mutex
+	err      error
+	calls    int
+	spans    []sdktrace.ReadOnlySpan
+	shutdown bool
+}
+
+func (e *fakeSpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls++
+	if e.err != nil {
+		return e.err
+	}
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *fakeSpanExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	e.shutdown = true
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *fakeSpanExporter) setErr(err error) {
+	e.mu.Lock()
+	e.err = err
+	e.mu.Unlock()
+}
+
+func (e *fakeSpanExporter) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls
+}
+
+func (e *fakeSpanExporter) Spans() []sdktrace.ReadOnlySpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan(nil), e.spans...)
+}
+
+// memLogExporter keeps the exported log records in memory.
+type memLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+	err     error
+}
+
+func (e *memLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func (e *memLogExporter) ForceFlush(context.Context) error { return nil }
+func (e *memLogExporter) Shutdown(context.Context) error   { return nil }
+
+// collector is an OTLP/HTTP server recording the requests it receives.
+type collector struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		c.requests = append(c.requests, r.Clone(context.Background()))
+		c.mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// address returns the host:port of the collector.
+func (c *collector) address() string {
+	return strings.TrimPrefix(c.URL, "http://")
+}
+
+// headers returns the values of header sent to path, in order.
+func (c *collector) headers(path, header string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var values []string
+	for _, r := range c.requests {
+		if r.URL.Path == path {
+			values = append(values, r.Header.Get(header))
+		}
+	}
+	return values
+}
+
+// errorRecorder collects the errors reported to the global error handler.
+type errorRecorder struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *errorRecorder) Handle(err error) {
+	r.mu.Lock()
+	r.errs = append(r.errs, err)
+	r.mu.Unlock()
+}
+
+func (r *errorRecorder) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+// recordErrors installs an errorRecorder as the global error handler until
+// the end of the test. Tests using it must not run in parallel.
+func recordErrors(t *testing.T) *errorRecorder {
+	t.Helper()
+	prev := otel.GetErrorHandler()
+	r := &errorRecorder{}
+	otel.SetErrorHandler(r)
+	t.Cleanup(func() { otel.SetErrorHandler(prev) })
+	return r
+}
+
+// captureStdout returns what the StdoutExporter writes while fn runs. Tests
+// using it must not run in parallel.
+func captureStdout(fn func()) string {
+	var buf syncBuffer
+	prev := stdout
+	stdout = &buf
+	defer func() { stdout = prev }()
+	fn()
+	return buf.String()
+}
+
+// grpcCollector is an OTLP/gRPC server recording the User-Agent of the
+// exports it receives for each signal.
+type grpcCollector struct {
+	mu         sync.Mutex
+	userAgents map[string][]string
+}
+
+// newGRPCCollector serves OTLP/gRPC on lis until the end of the test.
+func newGRPCCollector(t *testing.T, lis net.Listener) *grpcCollector {
+	t.Helper()
+	c := &grpcCollector{userAgents: make(map[string][]string)}
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, grpcTraceService{c: c})
+	colmetricpb.RegisterMetricsServiceServer(srv, grpcMetricsService{c: c})
+	collogpb.RegisterLogsServiceServer(srv, grpcLogsService{c: c})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return c
+}
+
+func (c *grpcCollector) record(ctx context.Context, signal string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.mu.Lock()
+	c.userAgents[signal] = append(c.userAgents[signal], strings.Join(md.Get("user-agent"), ","))
+	c.mu.Unlock()
+}
+
+// received returns the User-Agent of every export of signal, in order.
+func (c *grpcCollector) received(signal string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.userAgents[signal]...)
+}
+
+type grpcTraceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+	c *grpcCollector
+}
+
+func (s grpcTraceService) Export(ctx context.Context, _ *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	s.c.record(ctx, "traces")
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+type grpcMetricsService struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+	c *grpcCollector
+}
+
+func (s grpcMetricsService) Export(ctx context.Context, _ *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	s.c.record(ctx, "metrics")
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+type grpcLogsService struct {
+	collogpb.UnimplementedLogsServiceServer
+	c *grpcCollector
+}
+
+func (s grpcLogsService) Export(ctx context.Context, _ *collogpb.ExportLogsServiceRequest) (*collogpb.ExportLogsServiceResponse, error) {
+	s.c.record(ctx, "logs")
+	return &collogpb.ExportLogsServiceResponse{}, nil
+}
+
+// exportAll exports one span, one log record and one metric through p and
+// shuts it down.
+func exportAll(t *testing.T, p *Providers) {
+	t.Helper()
+	ctx := context.Background()
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "export")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("export"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+	counter, _ := p.MeterProvider.Meter("test").Int64Counter("exports")
+	counter.Add(ctx, 1)
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// metricsCollector is an OTLP/HTTP server keeping the metrics it receives.
+type metricsCollector struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	metrics []*metricpb.Metric
+}
+
+func newMetricsCollector(t *testing.T) *metricsCollector {
+	t.Helper()
+	c := &metricsCollector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var req colmetricpb.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Error(err)
+			return
+		}
+		c.mu.Lock()
+		for _, rm := range req.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				c.metrics = append(c.metrics, sm.Metrics...)
+			}
+		}
+		c.mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// address returns the host:port of the collector.
+func (c *metricsCollector) address() string {
+	return strings.TrimPrefix(c.URL, "http://")
+}
+
+// find returns the metrics named name received so far, in order.
+func (c *metricsCollector) find(name string) []*metricpb.Metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var found []*metricpb.Metric
+	for _, m := range c.metrics {
+		if m.Name == name {
+			found = append(found, m)
+		}
+	}
+	return found
+}
@@ -0,0 +1,185 @@
+package telemetry
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WebSocketSpanMode selects how NewHTTPHandler traces WebSocket upgrades,
+// whose connection outlives the request that opened it.
+type WebSocketSpanMode int
+
+const (
+	// WebSocketSpanEndsAtUpgrade ends the server span when the connection is
+	// hijacked for the WebSocket, recording a websocket.upgrade event.
+	WebSocketSpanEndsAtUpgrade WebSocketSpanMode = iota
+	// WebSocketSpanSkipped creates no span for upgrade requests.
+	WebSocketSpanSkipped
+	// WebSocketSpanCoversConnection keeps the span open until the handler
+	// returns, so it covers the whole WebSocket connection.
+	WebSocketSpanCoversConnection
+)
+
+type httpHandlerConfig struct {
+	webSocketMode WebSocketSpanMode
+}
+
+// HTTPHandlerOption configures NewHTTPHandler and
+// NewHTTPHandlerWithNameFormatter.
+type HTTPHandlerOption func(*httpHandlerConfig)
+
+// WithWebSocketSpans sets how WebSocket upgrade requests are traced. The
+// default is WebSocketSpanEndsAtUpgrade.
+func WithWebSocketSpans(mode WebSocketSpanMode) HTTPHandlerOption {
+	return func(c *httpHandlerConfig) {
+		c.webSocketMode = mode
+	}
+}
+
+// NewHTTPHandler wraps handler with the otelhttp instrumentation and names
+// each server span after the ServeMux pattern that matched the request, such
+// as /users/{id}, instead of the path, which keeps span names low-cardinality.
+// Requests matching no pattern keep the name "/".
+//
+// It also records the number of requests being served as the
+// http.server.active_requests up-down counter, with the http.request.method
+// attribute and, when handler is a ServeMux or one wrapped by RecoveryHandler
+// or LoggerMiddleware, the http.route of the pattern matching the request.
+//
+// The span of a WebSocket upgrade ends when the connection is upgraded, see
+// WithWebSocketSpans.
+func NewHTTPHandler(handler http.Handler, opts ...HTTPHandlerOption) http.Handler {
+	return NewHTTPHandlerWithNameFormatter(handler, RoutePattern, opts...)
+}
+
+// NewHTTPHandlerWithNameFormatter is like NewHTTPHandler but names each server
+// span with formatter, which is called after handler has served the request,
+// so r.Pattern is set by a ServeMux. An empty name keeps the span name.
+// Middleware between it and the ServeMux that replaces the request, such as
+// LoggerMiddleware, hides the pattern; register it per route instead.
+func NewHTTPHandlerWithNameFormatter(handler http.Handler, formatter func(r *http.Request) string, opts ...HTTPHandlerOption) http.Handler {
+	var cfg httpHandlerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	activeRequests, err := otel.Meter(name).Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("The number of active HTTP server requests"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attrs := metric.WithAttributes(activeRequestAttrs(handler, r)...)
+		activeRequests.Add(r.Context(), 1, attrs)
+		defer activeRequests.Add(r.Context(), -1, attrs)
+
+		// ServeMux records the matched pattern on r itself.
+		setName := func() {
+			if name := formatter(r); name != "" {
+				trace.SpanFromContext(r.Context()).SetName(name)
+			}
+		}
+		if cfg.webSocketMode == WebSocketSpanEndsAtUpgrade && isWebSocketUpgrade(r) {
+			w = &upgradeResponseWriter{ResponseWriter: w, onUpgrade: func() {
+				setName()
+				span := trace.SpanFromContext(r.Context())
+				span.AddEvent("websocket.upgrade")
+				span.End()
+			}}
+		}
+		handler.ServeHTTP(w, r)
+		setName()
+	})
+
+	// otelhttp names the span again once a ServeMux has set r.Pattern, so the
+	// formatter has to be its span name formatter too.
+	otelOpts := []otelhttp.Option{otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+		if name := formatter(r); name != "" {
+			return name
+		}
+		return operation
+	})}
+	if cfg.webSocketMode == WebSocketSpanSkipped {
+		otelOpts = append(otelOpts, otelhttp.WithFilter(func(r *http.Request) bool {
+			return !isWebSocketUpgrade(r)
+		}))
+	}
+	return otelhttp.NewHandler(named, "/", otelOpts...)
+}
+
+// middleware is a handler of this package wrapping next, such as
+// RecoveryHandler, which activeRequestAttrs looks through for the ServeMux.
+type middleware struct {
+	http.Handler
+	next http.Handler
+}
+
+// activeRequestAttrs returns the attributes of the active requests counter
+// for r. The pattern is only set on r once a ServeMux serves it, but the
+// counter must be incremented before, so the mux is asked for it up front.
+func activeRequestAttrs(handler http.Handler, r *http.Request) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(r.Method)}
+	for {
+		m, ok := handler.(middleware)
+		if !ok {
+			break
+		}
+		handler = m.next
+	}
+	if mux, ok := handler.(*http.ServeMux); ok {
+		_, pattern := mux.Handler(r)
+		if route := patternPath(pattern); route != "" {
+			attrs = append(attrs, semconv.HTTPRoute(route))
+		}
+	}
+	return attrs
+}
+
+// RoutePattern returns the path of the ServeMux pattern that matched r,
+// without its method and host, or "" if none did.
+func RoutePattern(r *http.Request) string {
+	return patternPath(r.Pattern)
+}
+
+// patternPath returns the path of a ServeMux pattern.
+func patternPath(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// Transport wraps base with the otelhttp instrumentation, so every request it
+// sends gets a client span and carries the trace context of its request
+// context through the global propagator. A nil base uses
+// http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// NewHTTPClient returns a copy of base whose transport is wrapped by
+// Transport. A nil base copies http.DefaultClient. Requests must be created
+// with the context of the calling span, for example with
+// http.NewRequestWithContext, for the client span to join its trace.
+func NewHTTPClient(base *http.Client) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	client := *base
+	client.Transport = Transport(base.Transport)
+	return &client
+}
@@ -0,0 +1,168 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// activeRequests returns the value of http.server.active_requests for route.
+func activeRequests(t *testing.T, reader sdkmetric.Reader, route string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.active_requests" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, _ := dp.Attributes.Value(semconv.HTTPRouteKey); v.AsString() == route {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestActiveRequestsThroughRecoveryHandler(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	setupGlobalTest(t, WithCustomMetricReader(reader))
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+	})
+	srv := httptest.NewServer(NewHTTPHandler(RecoveryHandler(LoggerMiddleware(mux))))
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"1", "2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(srv.URL + "/items/" + id)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	<-entered
+	<-entered
+
+	if got := activeRequests(t, reader, "/items/{id}"); got != 2 {
+		t.Errorf("active requests = %d, want 2", got)
+	}
+	close(release)
+	wg.Wait()
+	if got := activeRequests(t, reader, "/items/{id}"); got != 0 {
+		t.Errorf("active requests after completion = %d, want 0", got)
+	}
+}
+
+func TestRecoveryHandler(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+
+	handler := NewHTTPHandler(RecoveryHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("nil map")
+	})))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	spans := exp.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	// otelhttp sets the status of the 500 response again, without a
+	// description.
+	if got := spans[0].Status().Code; got != codes.Error {
+		t.Errorf("span status = %v, want Error", got)
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("span events = %v, want one exception", events)
+	}
+	if !hasAttribute(events[0], "exception.message", "nil map") {
+		t.Errorf("exception attributes = %v, want exception.message nil map", events[0].Attributes)
+	}
+	if !hasKey(events[0].Attributes, "exception.stacktrace") {
+		t.Error("exception has no stack trace")
+	}
+}
+
+func TestHTTPSpanNamedByRoute(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(http.ResponseWriter, *http.Request) {})
+
+	handlers := map[string]http.Handler{
+		"/users/{id}": NewHTTPHandler(mux),
+		"GET /users/{id}": NewHTTPHandlerWithNameFormatter(mux, func(r *http.Request) string {
+			return r.Method + " " + RoutePattern(r)
+		}),
+	}
+	for want, handler := range handlers {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/12345", nil))
+		spans := exp.Spans()
+		if got := spans[len(spans)-1].Name(); got != want {
+			t.Errorf("span name = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+	traceparents := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	ctx, parent := p.TracerProvider.Tracer("test").Start(context.Background(), "checkout")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewHTTPClient(nil).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want the client span and its parent", len(spans))
+	}
+	client := spans[0]
+	if client.SpanKind() != trace.SpanKindClient || client.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("span %q kind %v parent %v, want a client span of checkout", client.Name(), client.SpanKind(), client.Parent().SpanID())
+	}
+	want := fmt.Sprintf("00-%s-%s-01", client.SpanContext().TraceID(), client.SpanContext().SpanID())
+	if got := <-traceparents; got != want {
+		t.Errorf("traceparent = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// instrumentCache holds the instruments of one kind created by the helpers
+// below, by name.
+type instrumentCache[T any] struct {
+	mu          sync.Mutex
+	instruments map[string]T
+}
+
+// get returns the instrument named name, creating it with create on first
+// use. Failed creations are not cached.
+func (c *instrumentCache[T]) get(name string, create func() (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if inst, ok := c.instruments[name]; ok {
+		return inst, nil
+	}
+	inst, err := create()
+	if err != nil {
+		return inst, err
+	}
+	if c.instruments == nil {
+		c.instruments = make(map[string]T)
+	}
+	c.instruments[name] = inst
+	return inst, nil
+}
+
+func (c *instrumentCache[T]) reset() {
+	c.mu.Lock()
+	c.instruments = nil
+	c.mu.Unlock()
+}
+
+var (
+	int64Counters     instrumentCache[metric.Int64Counter]
+	float64Histograms instrumentCache[metric.Float64Histogram]
+	int64Gauges       instrumentCache[metric.Int64Gauge]
+)
+
+// resetInstruments forgets the cached instruments when the global meter
+// provider they belong to is replaced.
+func resetInstruments() {
+	int64Counters.reset()
+	float64Histograms.reset()
+	int64Gauges.reset()
+}
+
+// Int64Counter returns the counter named name of the Meter returned by
+// Meter(""), creating it on the first call. Later calls with the same name
+// return the same counter, whatever their description and unit, so packages
+// can share an instrument without registering it twice.
+func Int64Counter(name, description, unit string) (metric.Int64Counter, error) {
+	return int64Counters.get(name, func() (metric.Int64Counter, error) {
+		return Meter("").Int64Counter(name, metric.WithDescription(description), metric.WithUnit(unit))
+	})
+}
+
+// Float64Histogram is like Int64Counter for histograms.
+func Float64Histogram(name, description, unit string) (metric.Float64Histogram, error) {
+	return float64Histograms.get(name, func() (metric.Float64Histogram, error) {
+		return Meter("").Float64Histogram(name, metric.WithDescription(description), metric.WithUnit(unit))
+	})
+}
+
+// Int64Gauge is like Int64Counter for synchronous gauges.
+func Int64Gauge(name, description, unit string) (metric.Int64Gauge, error) {
+	return int64Gauges.get(name, func() (metric.Int64Gauge, error) {
+		return Meter("").Int64Gauge(name, metric.WithDescription(description), metric.WithUnit(unit))
+	})
+}
+
+// RegisterInt64ObservableGauge registers a gauge named name with the Meter
+// returned by Meter("") whose value is read from callback whenever metrics are
+// collected, for values such as a queue depth that are cheaper to read than to
+// push. The gauge stops being observed when the pipeline registered by the
+// last Setup is shut down, or earlier when the returned function is called.
+func RegisterInt64ObservableGauge(name string, callback func() int64) (unregister func(context.Context) error, err error) {
+	meter := Meter("")
+	gauge, err := meter.Int64ObservableGauge(name)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(gauge, callback())
+		return nil
+	}, gauge)
+	if err != nil {
+		return nil, err
+	}
+	unregister = func(context.Context) error { return reg.Unregister() }
+	if p := currentProviders.Load(); p != nil {
+		p.onShutdown(unregister)
+	}
+	return unregister, nil
+}
@@ -0,0 +1,122 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestInstrumentsAreShared(t *testing.T) {
+	errs := recordErrors(t)
+	reader := sdkmetric.NewManualReader()
+	setupGlobalTest(t, WithCustomMetricReader(reader))
+
+	c1, err := Int64Counter("jobs", "The number of jobs", "{job}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another package asking for the counter with a different description.
+	c2, err := Int64Counter("jobs", "Jobs run", "{job}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1 != c2 {
+		t.Error("Int64Counter returned two counters for the same name")
+	}
+	h1, _ := Float64Histogram("job.duration", "", "s")
+	h2, _ := Float64Histogram("job.duration", "", "s")
+	if h1 != h2 {
+		t.Error("Float64Histogram returned two histograms for the same name")
+	}
+	g1, _ := Int64Gauge("workers", "", "{worker}")
+	g2, _ := Int64Gauge("workers", "", "{worker}")
+	if g1 != g2 {
+		t.Error("Int64Gauge returned two gauges for the same name")
+	}
+
+	c1.Add(context.Background(), 1)
+	c2.Add(context.Background(), 2)
+	dps := collectMetric(t, reader, "jobs").(metricdata.Sum[int64]).DataPoints
+	if len(dps) != 1 || dps[0].Value != 3 {
+		t.Errorf("jobs data points = %+v, want one stream of 3", dps)
+	}
+	if got := errs.Errors(); len(got) != 0 {
+		t.Errorf("errors reported: %v", got)
+	}
+}
+
+func TestRegisterInt64ObservableGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	setupGlobalTest(t, WithCustomMetricReader(reader))
+
+	var depth atomic.Int64
+	depth.Store(7)
+	unregister, err := RegisterInt64ObservableGauge("queue.depth", depth.Load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gauge := func() []metricdata.DataPoint[int64] {
+		return collectMetric(t, reader, "queue.depth").(metricdata.Gauge[int64]).DataPoints
+	}
+	if dps := gauge(); len(dps) != 1 || dps[0].Value != 7 {
+		t.Errorf("queue.depth = %+v, want 7", dps)
+	}
+	depth.Store(3)
+	if dps := gauge(); len(dps) != 1 || dps[0].Value != 3 {
+		t.Errorf("queue.depth after a change = %+v, want 3", dps)
+	}
+
+	if err := unregister(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "queue.depth" {
+				t.Errorf("queue.depth after unregistering = %+v, want it not observed", m.Data)
+			}
+		}
+	}
+}
+
+func TestObservableGaugeUnregisteredAtShutdown(t *testing.T) {
+	p := setupGlobalTest(t)
+	// Register the gauge with a meter provider outliving the pipeline, so
+	// that only its unregistration stops the callback.
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer mp.Shutdown(context.Background())
+	otel.SetMeterProvider(mp)
+
+	var calls atomic.Int64
+	if _, err := RegisterInt64ObservableGauge("queue.depth", func() int64 {
+		calls.Add(1)
+		return 7
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("callback invoked %d times, want 1", got)
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("callback invoked %d times after Shutdown, want 1", got)
+	}
+}
@@ -0,0 +1,41 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// samplingPriorityKey is the attribute asking tail sampling collectors to
+// retain a span.
+const samplingPriorityKey = attribute.Key("sampling.priority")
+
+type keepKey struct{}
+
+// Keep returns a copy of ctx asking the collector to retain the spans started
+// with it, for example after an error was detected. The spans carry
+// sampling.priority=1 when WithSamplingPriority is used; configure the tail
+// sampling policy of the collector to keep them.
+func Keep(ctx context.Context) context.Context {
+	return context.WithValue(ctx, keepKey{}, true)
+}
+
+func kept(ctx context.Context) bool {
+	v, _ := ctx.Value(keepKey{}).(bool)
+	return v
+}
+
+// samplingPriorityProcessor sets sampling.priority=1 on the spans started
+// with a context marked by Keep.
+type samplingPriorityProcessor struct{}
+
+func (samplingPriorityProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if kept(parent) {
+		s.SetAttributes(samplingPriorityKey.Int(1))
+	}
+}
+
+func (samplingPriorityProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (samplingPriorityProcessor) Shutdown(context.Context) error   { return nil }
+func (samplingPriorityProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,35 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSamplingPriority(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithSamplingPriority(),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "before")
+	span.End()
+	_, span = tracer.Start(Keep(ctx), "kept")
+	span.End()
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	if hasKey(spans[0].Attributes(), samplingPriorityKey) {
+		t.Error("span started before Keep carries sampling.priority")
+	}
+	attrs := attribute.NewSet(spans[1].Attributes()...)
+	if v, _ := attrs.Value(samplingPriorityKey); v.AsInt64() != 1 {
+		t.Errorf("span started after Keep has sampling.priority %v, want 1", v.Emit())
+	}
+}
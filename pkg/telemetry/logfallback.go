@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// fallbackLogExporter writes the records the wrapped exporter fails to export
+// to a writer, one line per record.
+type fallbackLogExporter struct {
+	sdklog.Exporter
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (e *fallbackLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	if err == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for i := range records {
+		writeLogLine(&buf, &records[i])
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, werr := e.w.Write(buf.Bytes()); werr != nil {
+		return fmt.Errorf("%w; writing to the fallback writer: %v", err, werr)
+	}
+	return err
+}
+
+// writeLogLine formats r as a line such as
+//
+//	2025-01-02T15:04:05.000Z ERROR payment failed order.id=42
+func writeLogLine(buf *bytes.Buffer, r *sdklog.Record) {
+	ts := r.Timestamp()
+	if ts.IsZero() {
+		ts = r.ObservedTimestamp()
+	}
+	severity := r.SeverityText()
+	if severity == "" {
+		severity = r.Severity().String()
+	}
+	fmt.Fprintf(buf, "%s %s %s", ts.UTC().Format(time.RFC3339Nano), severity, r.Body().String())
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		fmt.Fprintf(buf, " %s=%s", kv.Key, kv.Value.String())
+		return true
+	})
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+)
+
+func emitPaymentFailed(logger log.Logger) {
+	var r log.Record
+	r.SetSeverity(log.SeverityError)
+	r.SetBody(log.StringValue("payment failed"))
+	r.AddAttributes(log.Int("order.id", 42))
+	logger.Emit(context.Background(), r)
+}
+
+func TestLogFallbackWriter(t *testing.T) {
+	recordErrors(t)
+	exp := &memLogExporter{err: errors.New("collector down")}
+	var fallback syncBuffer
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithSyncSpanProcessor(),
+		WithLogFallbackWriter(&fallback),
+	)
+
+	emitPaymentFailed(p.LoggerProvider.Logger("test"))
+
+	got := fallback.String()
+	if !strings.HasSuffix(got, " ERROR payment failed order.id=42\n") {
+		t.Errorf("fallback output = %q, want the failed record", got)
+	}
+}
+
+func TestLogFallbackWriterSkipsExportedRecords(t *testing.T) {
+	exp := &memLogExporter{}
+	var fallback syncBuffer
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithSyncSpanProcessor(),
+		WithLogFallbackWriter(&fallback),
+	)
+
+	emitPaymentFailed(p.LoggerProvider.Logger("test"))
+
+	if got := len(exp.Records()); got != 1 {
+		t.Errorf("exported %d records, want 1", got)
+	}
+	if got := fallback.String(); got != "" {
+		t.Errorf("fallback output = %q for an exported record, want none", got)
+	}
+}
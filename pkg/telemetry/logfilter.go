@@ -0,0 +1,135 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// logFilter is implemented by the filtering processors. Every processor sees
+// every record, so newLoggerProvider also consults the filters registered with
+// WithLogProcessor before the exporting processors it builds are called.
+type logFilter interface {
+	allow(ctx context.Context, r *sdklog.Record) bool
+}
+
+// filteredProcessor calls Processor only for the records allowed by filters.
+type filteredProcessor struct {
+	sdklog.Processor
+	filters []logFilter
+}
+
+func (p filteredProcessor) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	for _, f := range p.filters {
+		if !f.allow(ctx, r) {
+			return nil
+		}
+	}
+	return p.Processor.OnEmit(ctx, r)
+}
+
+// LevelFilter passes on the log records at or above a minimum severity that
+// can be changed while the application is running.
+type LevelFilter struct {
+	next sdklog.Processor
+	min  *atomic.Int64
+}
+
+var _ sdklog.Processor = (*LevelFilter)(nil)
+
+// NewLevelFilter returns a LevelFilter passing to next only the records whose
+// severity is at least the log.Severity stored in minLevel, for example
+// int64(log.SeverityInfo). Change the level with SetMinSeverity or by storing
+// to minLevel. When registered with WithLogProcessor, it also filters the
+// records exported by Setup; next may then be nil.
+func NewLevelFilter(next sdklog.Processor, minLevel *atomic.Int64) *LevelFilter {
+	return &LevelFilter{next: next, min: minLevel}
+}
+
+// SetMinSeverity changes the minimum severity of exported records.
+func (f *LevelFilter) SetMinSeverity(severity log.Severity) {
+	f.min.Store(int64(severity))
+}
+
+// MinSeverity returns the current minimum severity.
+func (f *LevelFilter) MinSeverity() log.Severity {
+	return log.Severity(f.min.Load())
+}
+
+func (f *LevelFilter) allow(_ context.Context, r *sdklog.Record) bool {
+	return r.Severity() >= f.MinSeverity()
+}
+
+func (f *LevelFilter) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	if f.next == nil || !f.allow(ctx, r) {
+		return nil
+	}
+	return f.next.OnEmit(ctx, r)
+}
+
+func (f *LevelFilter) Shutdown(ctx context.Context) error {
+	if f.next == nil {
+		return nil
+	}
+	return f.next.Shutdown(ctx)
+}
+
+func (f *LevelFilter) ForceFlush(ctx context.Context) error {
+	if f.next == nil {
+		return nil
+	}
+	return f.next.ForceFlush(ctx)
+}
+
+// AttributeLogFilter passes on the log records accepted by a predicate,
+// usually one inspecting their attributes.
+type AttributeLogFilter struct {
+	next sdklog.Processor
+	keep func(sdklog.Record) bool
+}
+
+var _ sdklog.Processor = (*AttributeLogFilter)(nil)
+
+// NewAttributeLogFilter returns an AttributeLogFilter passing to next only the
+// records for which keep returns true. When registered with WithLogProcessor,
+// it also filters the records exported by Setup; next may then be nil.
+// For example, to drop records marked sensitive=true:
+//
+//	telemetry.NewAttributeLogFilter(next, func(r sdklog.Record) bool {
+//		sensitive := false
+//		r.WalkAttributes(func(kv log.KeyValue) bool {
+//			sensitive = kv.Key == "sensitive" && kv.Value.AsBool()
+//			return !sensitive
+//		})
+//		return !sensitive
+//	})
+func NewAttributeLogFilter(next sdklog.Processor, keep func(sdklog.Record) bool) *AttributeLogFilter {
+	return &AttributeLogFilter{next: next, keep: keep}
+}
+
+func (f *AttributeLogFilter) allow(_ context.Context, r *sdklog.Record) bool {
+	return f.keep(*r)
+}
+
+func (f *AttributeLogFilter) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	if f.next == nil || !f.allow(ctx, r) {
+		return nil
+	}
+	return f.next.OnEmit(ctx, r)
+}
+
+func (f *AttributeLogFilter) Shutdown(ctx context.Context) error {
+	if f.next == nil {
+		return nil
+	}
+	return f.next.Shutdown(ctx)
+}
+
+func (f *AttributeLogFilter) ForceFlush(ctx context.Context) error {
+	if f.next == nil {
+		return nil
+	}
+	return f.next.ForceFlush(ctx)
+}
@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func emitSeverity(logger log.Logger, severity log.Severity) {
+	var r log.Record
+	r.SetSeverity(severity)
+	r.SetBody(log.StringValue(severity.String()))
+	logger.Emit(context.Background(), r)
+}
+
+func TestLevelFilterWrapsNext(t *testing.T) {
+	exp := &memLogExporter{}
+	var level atomic.Int64
+	level.Store(int64(log.SeverityWarn))
+	filter := NewLevelFilter(sdklog.NewSimpleProcessor(exp), &level)
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(filter))
+	defer lp.Shutdown(context.Background())
+	logger := lp.Logger("test")
+
+	emitSeverity(logger, log.SeverityInfo)
+	emitSeverity(logger, log.SeverityError)
+	filter.SetMinSeverity(log.SeverityDebug)
+	emitSeverity(logger, log.SeverityDebug)
+
+	records := exp.Records()
+	if len(records) != 2 {
+		t.Fatalf("exported %d records, want 2", len(records))
+	}
+	if records[0].Severity() != log.SeverityError || records[1].Severity() != log.SeverityDebug {
+		t.Errorf("exported severities %v and %v, want ERROR and DEBUG", records[0].Severity(), records[1].Severity())
+	}
+}
+
+func TestLevelFilterFiltersSetupExporters(t *testing.T) {
+	exp := &memLogExporter{}
+	var level atomic.Int64
+	level.Store(int64(log.SeverityInfo))
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithSyncSpanProcessor(),
+		WithLogProcessor(NewLevelFilter(nil, &level)),
+	)
+	logger := p.LoggerProvider.Logger("test")
+
+	emitSeverity(logger, log.SeverityDebug)
+	emitSeverity(logger, log.SeverityInfo)
+
+	if got := len(exp.Records()); got != 1 {
+		t.Errorf("exported %d records, want 1", got)
+	}
+}
+
+// notSensitive keeps the records without a sensitive=true attribute.
+func notSensitive(r sdklog.Record) bool {
+	sensitive := false
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		sensitive = kv.Key == "sensitive" && kv.Value.AsBool()
+		return !sensitive
+	})
+	return !sensitive
+}
+
+func emitSensitive(logger log.Logger, sensitive bool) {
+	var r log.Record
+	r.AddAttributes(log.Bool("sensitive", sensitive))
+	logger.Emit(context.Background(), r)
+}
+
+func TestAttributeLogFilterWrapsNext(t *testing.T) {
+	exp := &memLogExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(
+		NewAttributeLogFilter(sdklog.NewSimpleProcessor(exp), notSensitive)))
+	defer lp.Shutdown(context.Background())
+	logger := lp.Logger("test")
+
+	emitSensitive(logger, true)
+	emitSensitive(logger, false)
+
+	if got := len(exp.Records()); got != 1 {
+		t.Errorf("exported %d records, want 1", got)
+	}
+}
+
+func TestAttributeLogFilterFiltersSetupExporters(t *testing.T) {
+	exp := &memLogExporter{}
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithSyncSpanProcessor(),
+		WithLogProcessor(NewAttributeLogFilter(nil, notSensitive)),
+	)
+	logger := p.LoggerProvider.Logger("test")
+
+	emitSensitive(logger, true)
+	emitSensitive(logger, false)
+
+	if got := len(exp.Records()); got != 1 {
+		t.Errorf("exported %d records, want 1", got)
+	}
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/log"
+)
+
+// slogConfig configures the loggers created by NewSlogLogger.
+type slogConfig struct {
+	severities map[slog.Level]log.Severity
+}
+
+// SlogOption configures NewSlogLogger.
+type SlogOption func(*slogConfig)
+
+// WithSeverityMapping sets the OpenTelemetry severity of records logged at
+// the levels in mapping. Other levels keep the bridge's default mapping,
+// where slog.LevelDebug, LevelInfo, LevelWarn and LevelError become
+// log.SeverityDebug, SeverityInfo, SeverityWarn and SeverityError.
+func WithSeverityMapping(mapping map[slog.Level]log.Severity) SlogOption {
+	return func(c *slogConfig) {
+		c.severities = mapping
+	}
+}
+
+// NewSlogLogger returns a slog.Logger writing to the global LoggerProvider
+// through the slog bridge, for the named instrumentation scope. An empty name
+// uses the configured service name.
+func NewSlogLogger(name string, opts ...SlogOption) *slog.Logger {
+	var cfg slogConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var handler slog.Handler = otelslog.NewHandler(scopeName(name))
+	if len(cfg.severities) > 0 {
+		handler = severityHandler{handler, cfg.severities}
+	}
+	return slog.New(handler)
+}
+
+// severityHandler changes the level of records so that the bridge, which
+// converts slog.Level(n) to log.Severity(n+9), exports the mapped severity.
+type severityHandler struct {
+	slog.Handler
+	severities map[slog.Level]log.Severity
+}
+
+func (h severityHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sev, ok := h.severities[r.Level]; ok {
+		r.Level = slog.Level(sev - log.SeverityInfo)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h severityHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return severityHandler{h.Handler.WithAttrs(attrs), h.severities}
+}
+
+func (h severityHandler) WithGroup(name string) slog.Handler {
+	return severityHandler{h.Handler.WithGroup(name), h.severities}
+}
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying logger, returned by
+// LoggerFromContext.
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger stored in ctx by ContextWithLogger or
+// LoggerMiddleware. Otherwise it returns a logger writing to the global
+// LoggerProvider through the slog bridge, bound with the trace_id and span_id
+// of the span in ctx when there is one.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	logger := NewSlogLogger("")
+	if fields := LogFields(ctx); fields != nil {
+		logger = logger.With(fields...)
+	}
+	return logger
+}
+
+// LoggerMiddleware stores a logger bound with the trace and span IDs of each
+// request in its context, for handlers to retrieve with LoggerFromContext.
+// It must be wrapped by the HTTP instrumentation so the request has a span.
+func LoggerMiddleware(next http.Handler) http.Handler {
+	return middleware{next: next, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		next.ServeHTTP(w, r.WithContext(ContextWithLogger(ctx, LoggerFromContext(ctx))))
+	})}
+}
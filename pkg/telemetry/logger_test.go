@@ -0,0 +1,78 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordAttr returns the value of the attribute key of r as a string, or "".
+func recordAttr(r sdklog.Record, key string) string {
+	var value string
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == key {
+			value = kv.Value.AsString()
+			return false
+		}
+		return true
+	})
+	return value
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	exp := &memLogExporter{}
+	setupGlobalTest(t, WithCustomLogExporter(exp), WithSyncSpanProcessor())
+
+	var sc trace.SpanContext
+	handler := NewHTTPHandler(LoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc = trace.SpanContextFromContext(r.Context())
+		// The logger is bound with the IDs, so logging without the context
+		// is correlated too.
+		LoggerFromContext(r.Context()).Info("handled")
+	})))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	LoggerFromContext(context.Background()).Info("no span")
+
+	records := exp.Records()
+	if len(records) != 2 {
+		t.Fatalf("exported %d records, want 2", len(records))
+	}
+	if got := recordAttr(records[0], "trace_id"); got != sc.TraceID().String() {
+		t.Errorf("trace_id = %q, want %s", got, sc.TraceID())
+	}
+	if got := recordAttr(records[0], "span_id"); got != sc.SpanID().String() {
+		t.Errorf("span_id = %q, want %s", got, sc.SpanID())
+	}
+	if got := recordAttr(records[1], "trace_id"); got != "" {
+		t.Errorf("trace_id without a span = %q, want none", got)
+	}
+}
+
+func TestSeverityMapping(t *testing.T) {
+	exp := &memLogExporter{}
+	setupGlobalTest(t, WithCustomLogExporter(exp), WithSyncSpanProcessor())
+
+	NewSlogLogger("test").Warn("default")
+	logger := NewSlogLogger("test", WithSeverityMapping(map[slog.Level]log.Severity{
+		slog.LevelWarn: log.SeverityWarn4,
+	}))
+	logger.Warn("mapped")
+	logger.With("k", "v").Error("unmapped")
+
+	want := []log.Severity{log.SeverityWarn, log.SeverityWarn4, log.SeverityError}
+	records := exp.Records()
+	if len(records) != len(want) {
+		t.Fatalf("exported %d records, want %d", len(records), len(want))
+	}
+	for i, r := range records {
+		if r.Severity() != want[i] {
+			t.Errorf("%s severity = %v, want %v", r.Body().AsString(), r.Severity(), want[i])
+		}
+	}
+}
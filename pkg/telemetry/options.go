@@ -0,0 +1,1009 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promBridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// config holds the settings used to build the telemetry pipeline.
+type config struct {
+	exporterType ExporterType
+	otlpAddress  string
+	resources    *resource.Resource
+
+	spanBufferSize     int
+	spanBufferFallback io.Writer
+	// spanBuffer is the BufferedExporter created for WithSpanBuffer.
+	spanBuffer *BufferedExporter
+
+	spanProcessors []sdktrace.SpanProcessor
+	logProcessors  []sdklog.Processor
+	idGenerator    sdktrace.IDGenerator
+	sampler        sdktrace.Sampler
+
+	stdoutCompact bool
+
+	attributeValueLengthLimit int
+
+	retry *RetryConfig
+
+	syncExport bool
+
+	exemplarFilter       exemplar.Filter
+	metricExportInterval time.Duration
+	cardinalityLimit     int
+	metricProducers      []sdkmetric.Producer
+	views                []sdkmetric.View
+	deltaToCumulative    bool
+	temporalitySelector  sdkmetric.TemporalitySelector
+	aggregationSelector  sdkmetric.AggregationSelector
+
+	health   *pipelineHealth
+	sampling *samplerStats
+
+	exporterMetrics bool
+	pipelineMetrics *pipelineMetrics
+	exportCallback  func(ExportStats)
+	// selfCounters are the counters of the pipeline's own metrics, bound to
+	// its MeterProvider once it is created.
+	selfCounters []*selfCounter
+
+	logBatchOpts    []sdklog.BatchProcessorOption
+	logMaxQueueSize int
+	logFallback     io.Writer
+
+	spanMaxQueueSize, spanMaxBatchSize   int
+	spanScheduleDelay, spanExportTimeout time.Duration
+	eagerFlush                           bool
+
+	tracesURLPath, metricsURLPath, logsURLPath string
+
+	customHTTPClient *http.Client
+
+	tlsConfig     *tls.Config
+	insecure      *bool
+	tokenProvider tokenFunc
+	userAgent     string
+	zipkinURL     string
+	grpcConn      *grpc.ClientConn
+	keepalive     *keepalive.ClientParameters
+
+	failoverEndpoints []string
+	srvService        string
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+
+	setupTimeout   time.Duration
+	drainTimeout   time.Duration
+	failOpen       bool
+	registerGlobal bool
+	startupSpan    bool
+
+	additionalTraceExporters  []sdktrace.SpanExporter
+	additionalMetricExporters []sdkmetric.Exporter
+	additionalLogExporters    []sdklog.Exporter
+
+	customTraceExporter sdktrace.SpanExporter
+	customMetricReader  sdkmetric.Reader
+	customLogExporter   sdklog.Exporter
+
+	// errs holds the errors reported by options, returned by Validate.
+	errs []error
+}
+
+// Option configures the pipeline built by SetupOTelSDK.
+type Option func(*config)
+
+func newConfig(exporterType ExporterType, otlpAddress string, resources *resource.Resource, opts []Option) *config {
+	cfg := &config{
+		exporterType: exporterType,
+		otlpAddress:  otlpAddress,
+		resources:    resources,
+		// Default is 1m. Set to 3s for demonstrative purposes.
+		metricExportInterval: 3 * time.Second,
+		// Default is 5s. Set to 1s for demonstrative purposes.
+		spanScheduleDelay: time.Second,
+		health:            &pipelineHealth{},
+		sampling:          &samplerStats{},
+		registerGlobal:    true,
+		userAgent:         defaultUserAgent,
+	}
+	// Applied first, so explicit options take precedence.
+	cfg.spanBatchFromEnv()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// Validate reports invalid settings, such as an endpoint that is not a
+// host:port or does not match the exporter, or a non-positive interval, with
+// one error for each. It is called by Setup before any provider is created.
+func (c *config) Validate() error {
+	errs := c.errs
+	if len(c.failoverEndpoints) > 0 && c.exporterType != GrpcExporter && c.exporterType != HttpExporter {
+		errs = append(errs, errors.New("telemetry: failover endpoints require the gRPC or HTTP exporter"))
+	}
+	if c.srvService != "" && c.exporterType != GrpcExporter && c.exporterType != HttpExporter {
+		errs = append(errs, errors.New("telemetry: SRV endpoints require the gRPC or HTTP exporter"))
+	}
+	if _, ok := unixSocketPath(c.otlpAddress); ok && c.exporterType == HttpExporter {
+		errs = append(errs, errors.New("telemetry: Unix socket endpoints are only supported by the gRPC exporters"))
+	}
+	if _, ok := exporterNames[c.exporterType]; !ok {
+		errs = append(errs, fmt.Errorf("telemetry: unknown exporter type %d", c.exporterType))
+	}
+	if c.usesOTLP() {
+		if err := c.validateEndpoint(); err != nil {
+			errs = append(errs, err)
+		} else if err := c.checkEndpointPort(); err != nil {
+			// The port may be remapped, so a mismatch is only reported.
+			otel.Handle(err)
+		}
+		// Only plaintext chosen explicitly may carry the tokens.
+		if c.tokenProvider != nil && c.transportTLS() == nil && c.insecure == nil {
+			errs = append(errs, errors.New("telemetry: a token provider requires TLS; use WithInsecure(true) to send tokens over plaintext"))
+		}
+	}
+	if c.metricExportInterval <= 0 {
+		errs = append(errs, fmt.Errorf("telemetry: metric export interval must be positive, got %v", c.metricExportInterval))
+	}
+	if ka := c.keepalive; ka != nil && (ka.Time <= 0 || ka.Timeout <= 0) {
+		errs = append(errs, fmt.Errorf("telemetry: keepalive interval and timeout must be positive, got %v and %v", ka.Time, ka.Timeout))
+	}
+	if c.spanBufferFallback != nil && c.spanBufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("telemetry: span buffer size must be positive, got %d", c.spanBufferSize))
+	}
+	return errors.Join(errs...)
+}
+
+// usesOTLP reports whether any signal is sent with an OTLP exporter dialing
+// otlpAddress.
+func (c *config) usesOTLP() bool {
+	switch c.exporterType {
+	case HttpExporter:
+		return true
+	case GrpcExporter, ZipkinExporter:
+		// A connection set with WithGRPCConn carries its own address.
+		return c.grpcConn == nil
+	}
+	return false
+}
+
+// validateEndpoint reports an otlpAddress that is not a host:port. An empty
+// address selects the exporters' default.
+func (c *config) validateEndpoint() error {
+	if c.otlpAddress == "" {
+		return nil
+	}
+	if _, ok := unixSocketPath(c.otlpAddress); ok {
+		return nil
+	}
+	if strings.Contains(c.otlpAddress, "://") {
+		return fmt.Errorf("telemetry: invalid endpoint %q: must be host:port; use WithEndpointURL for URLs", c.otlpAddress)
+	}
+	host, port, err := net.SplitHostPort(c.otlpAddress)
+	if err != nil || strings.TrimSpace(host) == "" || port == "" {
+		return fmt.Errorf("telemetry: invalid endpoint %q: must be host:port", c.otlpAddress)
+	}
+	return nil
+}
+
+// checkEndpointPort reports an otlpAddress using the default port of the
+// other OTLP transport, which usually means the exporter or the port is wrong.
+func (c *config) checkEndpointPort() error {
+	_, port, err := net.SplitHostPort(c.otlpAddress)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case c.exporterType == HttpExporter && port == "4317":
+		return fmt.Errorf("telemetry: endpoint %q uses the OTLP/gRPC port 4317 with the HTTP exporter, which expects 4318", c.otlpAddress)
+	case c.exporterType != HttpExporter && port == "4318":
+		return fmt.Errorf("telemetry: endpoint %q uses the OTLP/HTTP port 4318 with the gRPC exporter, which expects 4317", c.otlpAddress)
+	}
+	return nil
+}
+
+// WithSpanBuffer places a bounded in-memory buffer holding up to size spans in
+// front of the trace exporter. Spans that do not fit in the buffer, or that the
+// exporter fails to send, are written to fallback instead of being dropped.
+// A nil fallback writes to os.Stderr. The spans written to fallback are
+// counted by the otelcore.span_buffer.overflow counter of the pipeline's
+// MeterProvider. Providers.ForceFlush exports the buffered spans too.
+func WithSpanBuffer(size int, fallback io.Writer) Option {
+	return func(c *config) {
+		if fallback == nil {
+			fallback = os.Stderr
+		}
+		c.spanBufferSize = size
+		c.spanBufferFallback = fallback
+	}
+}
+
+// WithSpanProcessor registers additional span processors with the
+// TracerProvider. They run alongside the processor that exports spans.
+// Multiple calls accumulate.
+func WithSpanProcessor(processors ...sdktrace.SpanProcessor) Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, processors...)
+	}
+}
+
+// WithDynamicAttributes calls fn with the start context of every span and sets
+// the returned attributes on the span. Use it for attributes only known at
+// request time, such as the active deployment color.
+func WithDynamicAttributes(fn func(ctx context.Context) []attribute.KeyValue) Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, dynamicAttributesProcessor{fn: fn})
+	}
+}
+
+// WithDefaultSpanAttributes sets attrs on every span when it starts, unless
+// the span was started with its own value for an attribute. Unlike resource
+// attributes they belong to each span and can be overwritten with
+// Span.SetAttributes.
+func WithDefaultSpanAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, defaultAttributesProcessor{attrs: attrs})
+	}
+}
+
+// WithBaggageToAttributes sets the baggage members named by keys, found in the
+// start context of every span, as span attributes, for example to filter
+// traces by a propagated tenant_id. Without keys every member is copied.
+func WithBaggageToAttributes(keys ...string) Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, baggageProcessor{keys: keys})
+	}
+}
+
+// WithCodeAttributes records the function that started each span, found by
+// skipping the frames of the OpenTelemetry packages and of this package, as
+// the code.namespace, code.function, code.filepath and code.lineno
+// attributes. Walking the stack on every span start has a cost, so it is best
+// used while debugging.
+func WithCodeAttributes() Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, codeProcessor{})
+	}
+}
+
+// WithSamplingPriority sets sampling.priority=1 on the spans started with a
+// context returned by Keep, so that a tail sampling collector retains them.
+func WithSamplingPriority() Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, samplingPriorityProcessor{})
+	}
+}
+
+// WithSlowSpanThreshold tags the spans lasting longer than d with the
+// attribute slow=true and a "slow" event at their end, so slow operations are
+// easy to query. Spans are tagged on the export path because the SDK does not
+// allow changing ended spans.
+func WithSlowSpanThreshold(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: slow span threshold must be positive, got %v", d))
+			return
+		}
+		c.spanProcessors = append(c.spanProcessors, slowSpanProcessor{threshold: d})
+	}
+}
+
+// WithLogProcessor registers additional log processors with the
+// LoggerProvider. They run before the processor that exports log records, so
+// changes they make to a record are exported. Multiple calls accumulate.
+func WithLogProcessor(processors ...sdklog.Processor) Option {
+	return func(c *config) {
+		c.logProcessors = append(c.logProcessors, processors...)
+	}
+}
+
+// WithInheritedAttributes makes child spans inherit the attributes named by keys
+// from their parent span. See NewAttributeInheritanceProcessor.
+func WithInheritedAttributes(keys ...attribute.Key) Option {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, NewAttributeInheritanceProcessor(keys...))
+	}
+}
+
+// WithAttributeValueLengthLimit truncates string attribute values of spans
+// and log records, such as long SQL statements, to limit characters so they
+// do not bloat export payloads. The default, like
+// OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT, is no limit.
+func WithAttributeValueLengthLimit(limit int) Option {
+	return func(c *config) {
+		if limit <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: attribute value length limit must be positive, got %d", limit))
+			return
+		}
+		c.attributeValueLengthLimit = limit
+	}
+}
+
+// WithStdoutCompact makes the StdoutExporter write each span, metric batch and
+// log record as a single line of JSON instead of pretty-printing it.
+func WithStdoutCompact() Option {
+	return func(c *config) {
+		c.stdoutCompact = true
+	}
+}
+
+// WithIDGenerator sets the generator used for new trace and span IDs.
+// By default the SDK's random generator is used.
+func WithIDGenerator(gen sdktrace.IDGenerator) Option {
+	return func(c *config) {
+		c.idGenerator = gen
+	}
+}
+
+// RetryConfig defines how the OTLP exporters retry failed exports.
+// Its fields mirror the RetryConfig types of the OTLP exporter packages.
+type RetryConfig struct {
+	// Enabled indicates whether to retry sending batches in case of export
+	// failure.
+	Enabled bool
+	// InitialInterval is the time to wait after the first failure before
+	// retrying.
+	InitialInterval time.Duration
+	// MaxInterval is the upper bound on backoff interval.
+	MaxInterval time.Duration
+	// MaxElapsedTime is the maximum amount of time (including retries) spent
+	// trying to send a request/batch.
+	MaxElapsedTime time.Duration
+}
+
+// WithRetryConfig sets the retry policy of every OTLP exporter.
+// By default the exporters use the SDK's retry policy.
+func WithRetryConfig(rc RetryConfig) Option {
+	return func(c *config) {
+		c.retry = &rc
+	}
+}
+
+// WithSyncSpanProcessor exports every span and log record synchronously as it
+// ends instead of batching them. This is meant for interactive debugging and
+// should not be used in production.
+func WithSyncSpanProcessor() Option {
+	return func(c *config) {
+		c.syncExport = true
+	}
+}
+
+// WithExemplarFilter sets the filter deciding which measurements are offered
+// as exemplars.
+func WithExemplarFilter(filter exemplar.Filter) Option {
+	return func(c *config) {
+		c.exemplarFilter = filter
+	}
+}
+
+// WithTraceBasedExemplars attaches exemplars only to measurements recorded
+// within a sampled span, so metrics can be correlated with traces.
+func WithTraceBasedExemplars() Option {
+	return WithExemplarFilter(exemplar.TraceBasedFilter)
+}
+
+// WithSampler sets the sampler used by the TracerProvider.
+// By default the SDK's parent-based always-on sampler is used.
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(c *config) {
+		c.sampler = sampler
+	}
+}
+
+// WithMetricExportInterval sets how often metrics are collected and exported.
+func WithMetricExportInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.metricExportInterval = d
+	}
+}
+
+// WithSpanMaxQueueSize sets the maximum number of spans the batch processor
+// queues before dropping new ones. It overrides OTEL_BSP_MAX_QUEUE_SIZE; the
+// default is 2048.
+func WithSpanMaxQueueSize(size int) Option {
+	return func(c *config) {
+		if size <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: span max queue size must be positive, got %d", size))
+			return
+		}
+		c.spanMaxQueueSize = size
+	}
+}
+
+// WithSpanMaxExportBatchSize sets the number of queued spans that makes the
+// batch processor export immediately instead of waiting for its timer. The
+// default is 512, or OTEL_BSP_MAX_EXPORT_BATCH_SIZE; sizes above the queue
+// size are lowered to it.
+func WithSpanMaxExportBatchSize(size int) Option {
+	return func(c *config) {
+		if size <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: span max export batch size must be positive, got %d", size))
+			return
+		}
+		c.spanMaxBatchSize = size
+	}
+}
+
+// WithEagerFlush exports spans as soon as the batch processor's queue is
+// full, by making the export batch as large as the queue, instead of waiting
+// for the timer. It suits bursty workloads.
+func WithEagerFlush() Option {
+	return func(c *config) {
+		c.eagerFlush = true
+	}
+}
+
+// WithLogFallbackWriter writes the log records the exporter fails to send,
+// after its own retries, to w as one human-readable line each, so critical
+// logs are not silently lost while the collector is unreachable. A nil w
+// writes to os.Stderr.
+func WithLogFallbackWriter(w io.Writer) Option {
+	return func(c *config) {
+		if w == nil {
+			w = os.Stderr
+		}
+		c.logFallback = w
+	}
+}
+
+// WithLogBatchTimeout sets how long the log batch processor waits for an export
+// to complete before cancelling it.
+func WithLogBatchTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: log batch timeout must be positive, got %v", d))
+			return
+		}
+		c.logBatchOpts = append(c.logBatchOpts, sdklog.WithExportTimeout(d))
+	}
+}
+
+// WithLogMaxQueueSize sets the maximum number of log records the batch
+// processor queues before dropping new ones.
+func WithLogMaxQueueSize(size int) Option {
+	return func(c *config) {
+		if size <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: log max queue size must be positive, got %d", size))
+			return
+		}
+		c.logMaxQueueSize = size
+		c.logBatchOpts = append(c.logBatchOpts, sdklog.WithMaxQueueSize(size))
+	}
+}
+
+// WithLogExportInterval sets the maximum time the log batch processor waits
+// before exporting queued records.
+func WithLogExportInterval(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: log export interval must be positive, got %v", d))
+			return
+		}
+		c.logBatchOpts = append(c.logBatchOpts, sdklog.WithExportInterval(d))
+	}
+}
+
+// WithHTTPTracesURLPath overrides the URL path the HTTP trace exporter sends
+// to. The default is /v1/traces.
+func WithHTTPTracesURLPath(path string) Option {
+	return func(c *config) {
+		c.tracesURLPath = path
+	}
+}
+
+// WithHTTPMetricsURLPath overrides the URL path the HTTP metric exporter sends
+// to. The default is /v1/metrics.
+func WithHTTPMetricsURLPath(path string) Option {
+	return func(c *config) {
+		c.metricsURLPath = path
+	}
+}
+
+// WithHTTPLogsURLPath overrides the URL path the HTTP log exporter sends to.
+// The default is /v1/logs.
+func WithHTTPLogsURLPath(path string) Option {
+	return func(c *config) {
+		c.logsURLPath = path
+	}
+}
+
+// WithHTTPClient makes the OTLP/HTTP exporters send with client, for example
+// one going through an authenticated forward proxy. The client is used as is:
+// TLS options such as WithCACertificate do not apply to it, so configure TLS
+// on its transport, and client.Timeout bounds each export when it is set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		if client == nil {
+			c.errs = append(c.errs, errors.New("telemetry: HTTP client must not be nil"))
+			return
+		}
+		c.customHTTPClient = client
+	}
+}
+
+// WithExporter selects the exporter, replacing the exporterType passed to
+// SetupOTelSDK.
+func WithExporter(exporterType ExporterType) Option {
+	return func(c *config) {
+		c.exporterType = exporterType
+	}
+}
+
+// WithEndpoint sets the host:port of the collector, replacing the otlpAddress
+// passed to SetupOTelSDK. Use WithEndpointURL for URLs. The gRPC exporters
+// also accept a Unix socket such as unix:///var/run/otel.sock.
+func WithEndpoint(otlpAddress string) Option {
+	return func(c *config) {
+		c.otlpAddress = otlpAddress
+	}
+}
+
+// WithSRVEndpoint takes the collector address from the DNS SRV record named
+// service, such as _otlp._tcp.collector.example.com, instead of a static
+// host:port. The record is resolved by Setup, which fails when it cannot be,
+// and the target with the lowest priority is used. The gRPC exporters resolve
+// the record again whenever they reconnect; the HTTP exporters keep the
+// address resolved at startup. It replaces the otlpAddress passed to
+// SetupOTelSDK.
+func WithSRVEndpoint(service string) Option {
+	return func(c *config) {
+		c.srvService = service
+	}
+}
+
+// WithResource sets the resource describing the service, replacing the
+// resources passed to SetupOTelSDK.
+func WithResource(res *resource.Resource) Option {
+	return func(c *config) {
+		c.resources = res
+	}
+}
+
+// WithEndpointURL sets the collector address from a URL such as
+// https://otlp.example.com:4318, the form used by OTEL_EXPORTER_OTLP_ENDPOINT.
+// The scheme selects TLS (https) or a plaintext connection (http), and a path
+// is used as the base of the HTTP exporters' URL paths, which become
+// <path>/v1/traces, <path>/v1/metrics and <path>/v1/logs. A plain host:port or
+// a unix:// socket is accepted too and only sets the address.
+// It replaces the otlpAddress passed to SetupOTelSDK.
+func WithEndpointURL(rawURL string) Option {
+	return func(c *config) {
+		if _, ok := unixSocketPath(rawURL); ok || !strings.Contains(rawURL, "://") {
+			c.otlpAddress = rawURL
+			return
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: invalid endpoint URL %q: must be a URL such as http://localhost:4318", rawURL))
+			return
+		}
+		switch u.Scheme {
+		case "http":
+			insecure := true
+			c.insecure = &insecure
+		case "https":
+			insecure := false
+			c.insecure = &insecure
+		default:
+			c.errs = append(c.errs, fmt.Errorf("telemetry: invalid endpoint URL %q: scheme must be http or https", rawURL))
+			return
+		}
+		c.otlpAddress = u.Host
+		if base := strings.TrimSuffix(u.Path, "/"); base != "" {
+			c.tracesURLPath = base + "/v1/traces"
+			c.metricsURLPath = base + "/v1/metrics"
+			c.logsURLPath = base + "/v1/logs"
+		}
+	}
+}
+
+// WithCardinalityLimit caps the number of distinct attribute sets aggregated
+// per instrument. Measurements exceeding the limit are aggregated into a
+// single overflow series carrying the otel.metric.overflow=true attribute.
+// The limit only applies to the MeterProvider built by Setup.
+func WithCardinalityLimit(limit int) Option {
+	return func(c *config) {
+		if limit <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: cardinality limit must be positive, got %d", limit))
+			return
+		}
+		c.cardinalityLimit = limit
+	}
+}
+
+// WithSetupTimeout bounds the time each signal may take to create its
+// exporter, for example while resolving the collector address, so a slow
+// collector cannot hang startup. The error returned on timeout names the
+// signal.
+func WithSetupTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: setup timeout must be positive, got %v", d))
+			return
+		}
+		c.setupTimeout = d
+	}
+}
+
+// WithDrainTimeout bounds the time shutdown spends exporting the telemetry
+// still queued by each signal before shutting its provider down. Without it,
+// draining is only bounded by the context passed to shutdown.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: drain timeout must be positive, got %v", d))
+			return
+		}
+		c.drainTimeout = d
+	}
+}
+
+// WithFailOpen keeps the application running when telemetry cannot be set up.
+// If the exporter of a signal cannot be created, the error is reported to the
+// global error handler and a noop provider is installed for that signal
+// instead of Setup returning an error. Invalid options, such as a malformed
+// endpoint, disable every signal the same way.
+func WithFailOpen() Option {
+	return func(c *config) {
+		c.failOpen = true
+	}
+}
+
+// WithAdditionalTraceExporter sends spans to exp in addition to the exporter
+// selected by the ExporterType. Each exporter gets its own span processor and
+// is shut down with the TracerProvider. Multiple calls accumulate.
+func WithAdditionalTraceExporter(exp sdktrace.SpanExporter) Option {
+	return func(c *config) {
+		c.additionalTraceExporters = append(c.additionalTraceExporters, exp)
+	}
+}
+
+// WithAdditionalMetricExporter sends metrics to exp in addition to the exporter
+// selected by the ExporterType. Each exporter gets its own periodic reader and
+// is shut down with the MeterProvider. Multiple calls accumulate.
+func WithAdditionalMetricExporter(exp sdkmetric.Exporter) Option {
+	return func(c *config) {
+		c.additionalMetricExporters = append(c.additionalMetricExporters, exp)
+	}
+}
+
+// WithAdditionalLogExporter sends log records to exp in addition to the
+// exporter selected by the ExporterType. Each exporter gets its own log
+// processor and is shut down with the LoggerProvider. Multiple calls accumulate.
+func WithAdditionalLogExporter(exp sdklog.Exporter) Option {
+	return func(c *config) {
+		c.additionalLogExporters = append(c.additionalLogExporters, exp)
+	}
+}
+
+// WithCustomTraceExporter sends spans to exp instead of the exporter selected
+// by the ExporterType, for backends this package has no exporter for. The
+// span processors and wrappers set by the other options still apply.
+func WithCustomTraceExporter(exp sdktrace.SpanExporter) Option {
+	return func(c *config) {
+		c.customTraceExporter = exp
+	}
+}
+
+// WithCustomMetricReader registers reader with the MeterProvider instead of
+// the periodic reader of the exporter selected by the ExporterType. The
+// reader is used as is, so options configuring that exporter, its interval
+// or its producers do not apply to it.
+func WithCustomMetricReader(reader sdkmetric.Reader) Option {
+	return func(c *config) {
+		c.customMetricReader = reader
+	}
+}
+
+// WithCustomLogExporter sends log records to exp instead of the exporter
+// selected by the ExporterType. The log processors set by the other options
+// still apply.
+func WithCustomLogExporter(exp sdklog.Exporter) Option {
+	return func(c *config) {
+		c.customLogExporter = exp
+	}
+}
+
+// WithoutGlobalRegistration builds the providers without installing them, or
+// the propagator, as the OpenTelemetry globals. Use the Providers returned by
+// Setup to run several independent pipelines in one process. Package helpers
+// such as Tracer and HealthHandler keep reporting on the global pipeline.
+func WithoutGlobalRegistration() Option {
+	return func(c *config) {
+		c.registerGlobal = false
+	}
+}
+
+// WithStartupSpan records a span named service.startup once the pipeline is
+// set up, starting when the process started and ending when Setup returns.
+// The process start time is read from /proc on Linux; on other systems the
+// span starts when this package was initialized, before main runs. It
+// carries the service name, version, deployment environment and vcs.revision
+// of the resource, the exporter and collector address, and the boot time in
+// seconds as otelcore.startup.duration.
+func WithStartupSpan() Option {
+	return func(c *config) {
+		c.startupSpan = true
+	}
+}
+
+// spanQueueSize returns the size of the batch span processor queue.
+func (c *config) spanQueueSize() int {
+	if c.spanMaxQueueSize > 0 {
+		return c.spanMaxQueueSize
+	}
+	return sdktrace.DefaultMaxQueueSize
+}
+
+// logQueueSize returns the size of the batch log processor queue.
+func (c *config) logQueueSize() int {
+	if c.logMaxQueueSize > 0 {
+		return c.logMaxQueueSize
+	}
+	// The default of sdklog.NewBatchProcessor, which does not export it.
+	return 2048
+}
+
+// transportTLS returns the TLS configuration of the OTLP exporters, or nil
+// for plaintext connections.
+func (c *config) transportTLS() *tls.Config {
+	switch {
+	case c.insecure != nil && *c.insecure:
+		return nil
+	case c.tlsConfig != nil:
+		return c.tlsConfig
+	case c.insecure != nil:
+		// Verify the collector against the system certificate pool.
+		return &tls.Config{}
+	}
+	return nil
+}
+
+// tls returns the TLS configuration used by the OTLP exporters, creating an
+// empty one for options to fill in.
+func (c *config) tls() *tls.Config {
+	if c.tlsConfig == nil {
+		c.tlsConfig = &tls.Config{}
+	}
+	return c.tlsConfig
+}
+
+// WithInsecure selects plaintext (true) or TLS (false) connections to the
+// collector for the OTLP exporters, like OTEL_EXPORTER_OTLP_INSECURE. With
+// TLS and no WithCACertificate, the collector is verified against the system
+// certificate pool. Without this option, connections are plaintext unless a
+// TLS option is used.
+func WithInsecure(insecure bool) Option {
+	return func(c *config) {
+		c.insecure = &insecure
+	}
+}
+
+// WithCACertificate makes the OTLP exporters connect over TLS and verify the
+// collector against the PEM encoded CA certificates in caFile.
+func WithCACertificate(caFile string) Option {
+	return func(c *config) {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: reading CA certificate: %w", err))
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: no certificates found in %s", caFile))
+			return
+		}
+		c.tls().RootCAs = pool
+	}
+}
+
+// WithClientCertificate makes the OTLP exporters connect over TLS and present
+// the PEM encoded key pair in certFile and keyFile to the collector.
+// Combine it with WithCACertificate for mutual TLS with a private CA.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *config) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: loading client certificate: %w", err))
+			return
+		}
+		c.tls().Certificates = append(c.tls().Certificates, cert)
+	}
+}
+
+// WithView registers views with the MeterProvider to customize the streams
+// produced by instruments. Multiple calls accumulate.
+func WithView(views ...sdkmetric.View) Option {
+	return func(c *config) {
+		c.views = append(c.views, views...)
+	}
+}
+
+// WithPrometheusProducer exports the metrics of collectors registered with
+// gatherer, such as prometheus.DefaultGatherer, through the metric pipeline
+// alongside the OpenTelemetry instruments.
+func WithPrometheusProducer(gatherer prometheus.Gatherer) Option {
+	return func(c *config) {
+		c.metricProducers = append(c.metricProducers, promBridge.NewMetricProducer(promBridge.WithGatherer(gatherer)))
+	}
+}
+
+// WithTemporalitySelector sets the aggregation temporality of each instrument
+// kind for the OTLP and stdout metric exporters, for backends that want delta
+// counters but cumulative up-down counters, for example. The default is
+// cumulative for every kind.
+func WithTemporalitySelector(selector sdkmetric.TemporalitySelector) Option {
+	return func(c *config) {
+		c.temporalitySelector = selector
+	}
+}
+
+// WithExponentialHistograms aggregates histograms into base-2 exponential
+// buckets, which need less space than explicit buckets for the same
+// precision. Views setting an aggregation, such as HistogramBuckets, still
+// take precedence for the instruments they match.
+func WithExponentialHistograms() Option {
+	return WithDefaultAggregation(func(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+		if k == sdkmetric.InstrumentKindHistogram {
+			return sdkmetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}
+		}
+		return sdkmetric.DefaultAggregationSelector(k)
+	})
+}
+
+// WithDefaultAggregation sets the aggregation of the instruments of each kind
+// that no view matches, for example to give every histogram the same
+// explicit buckets, so the policy is set in one place. selector is used by
+// the OTLP and stdout metric exporters; return
+// sdkmetric.DefaultAggregationSelector(k) for kinds it does not change.
+// It replaces WithExponentialHistograms.
+func WithDefaultAggregation(selector sdkmetric.AggregationSelector) Option {
+	return func(c *config) {
+		c.aggregationSelector = selector
+	}
+}
+
+// WithDeltaToCumulative converts delta sums and histograms to cumulative ones
+// before they are exported, so backends such as Prometheus see monotonic
+// counters even when instruments or producers report delta temporality.
+// Series without a data point for 5 minutes are forgotten, and delta
+// exponential histograms are dropped and reported to the global error handler.
+func WithDeltaToCumulative() Option {
+	return func(c *config) {
+		c.deltaToCumulative = true
+	}
+}
+
+// WithGRPCConn makes the gRPC exporters of all signals share conn instead of
+// dialing the collector themselves. The address, TLS, user agent and token
+// options are not applied to conn; configure them when dialing it. conn is
+// not closed when the pipeline shuts down.
+func WithGRPCConn(conn *grpc.ClientConn) Option {
+	return func(c *config) {
+		c.grpcConn = conn
+	}
+}
+
+// WithExporterMetrics records metrics about the pipeline itself through the
+// pipeline's own MeterProvider: the otelcore.exporter.export.success and
+// otelcore.exporter.export.failure counters, and the
+// otelcore.exporter.queue.length gauge of spans and log records waiting to be
+// exported, and the otelcore.queue.utilization gauge of the fraction of the
+// batch queue they fill, from 0 to 1, to alert on collector backpressure.
+// Each carries an otelcore.signal attribute naming the signal.
+func WithExporterMetrics() Option {
+	return func(c *config) {
+		c.exporterMetrics = true
+	}
+}
+
+// WithFailoverEndpoints adds collectors, as host:port, that spans are sent to
+// when the collector at otlpAddress is failing. When an export fails after
+// the exporter's own retries, the endpoints are tried in order and the first
+// one to succeed receives the following exports until it fails in turn.
+// Every collector is given at most its share of the export timeout, and no
+// more than 10 seconds, so a hanging one leaves time for the others.
+// Only the trace pipeline fails over.
+func WithFailoverEndpoints(endpoints []string) Option {
+	return func(c *config) {
+		c.failoverEndpoints = endpoints
+	}
+}
+
+// WithCircuitBreaker stops sending spans to a collector that is persistently
+// down. After threshold consecutive failed exports, spans are dropped without
+// contacting the collector until cooldown has passed; then one export is
+// attempted to decide whether to resume. Dropped spans are counted by the
+// otelcore.circuit_breaker.dropped counter of the pipeline's MeterProvider.
+// Only the trace pipeline is guarded.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *config) {
+		if threshold <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: circuit breaker threshold must be positive, got %d", threshold))
+			return
+		}
+		if cooldown <= 0 {
+			c.errs = append(c.errs, fmt.Errorf("telemetry: circuit breaker cooldown must be positive, got %v", cooldown))
+			return
+		}
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+// WithZipkinURL sets the URL of the Zipkin collector used by ZipkinExporter.
+// It defaults to OTEL_EXPORTER_ZIPKIN_ENDPOINT, or
+// http://localhost:9411/api/v2/spans when that is unset.
+func WithZipkinURL(url string) Option {
+	return func(c *config) {
+		c.zipkinURL = url
+	}
+}
+
+// WithKeepalive makes the gRPC exporters ping the collector after interval
+// without activity, and close the connection when a ping is not answered
+// within timeout, so that load balancers do not drop idle connections.
+// Pings are sent even when no export is in flight.
+func WithKeepalive(interval, timeout time.Duration) Option {
+	return func(c *config) {
+		c.keepalive = &keepalive.ClientParameters{
+			Time:                interval,
+			Timeout:             timeout,
+			PermitWithoutStream: true,
+		}
+	}
+}
+
+// WithExportCallback calls fn after every export of a batch of spans with the
+// running export counts, for example to drive a dashboard or apply
+// backpressure. fn is called from the exporting goroutine and must not block.
+func WithExportCallback(fn func(ExportStats)) Option {
+	return func(c *config) {
+		c.exportCallback = fn
+	}
+}
+
+// WithUserAgent sets the User-Agent sent with every OTLP export, so that
+// collectors can route and rate-limit by client. It defaults to
+// "go-otel-core/<version>".
+func WithUserAgent(userAgent string) Option {
+	return func(c *config) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTokenProvider sends a bearer token fetched from fn with every OTLP
+// export, for backends using short-lived tokens. Errors returned by fn fail
+// the export and are reported to the global error handler. Tokens are only
+// sent over TLS unless WithInsecure(true) selects plaintext explicitly.
+func WithTokenProvider(fn func(ctx context.Context) (string, error)) Option {
+	return func(c *config) {
+		c.tokenProvider = fn
+	}
+}
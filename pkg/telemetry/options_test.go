@@ -0,0 +1,625 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func TestCardinalityLimitOverflow(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p := setupTest(t, WithCardinalityLimit(3), WithCustomMetricReader(reader))
+
+	ctx := context.Background()
+	counter, err := p.MeterProvider.Meter("test").Int64Counter("requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("user", fmt.Sprint(i))))
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if got := len(sum.DataPoints); got != 3 {
+		t.Fatalf("got %d series, want 3", got)
+	}
+	overflow := attribute.NewSet(attribute.Bool("otel.metric.overflow", true))
+	for _, dp := range sum.DataPoints {
+		if dp.Attributes.Equals(&overflow) {
+			if dp.Value != 3 {
+				t.Errorf("overflow series = %d, want 3", dp.Value)
+			}
+			return
+		}
+	}
+	t.Error("no overflow series")
+}
+
+func TestCardinalityLimitDoesNotSetEnv(t *testing.T) {
+	t.Setenv("OTEL_GO_X_CARDINALITY_LIMIT", "")
+	setupTest(t, WithCardinalityLimit(3))
+	if v := os.Getenv("OTEL_GO_X_CARDINALITY_LIMIT"); v != "" {
+		t.Errorf("OTEL_GO_X_CARDINALITY_LIMIT = %q, want it unset", v)
+	}
+}
+
+func TestValidateRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		exporterType ExporterType
+		address      string
+		opts         []Option
+		want         string
+	}{
+		{"URL as endpoint", GrpcExporter, "http://collector:4317", nil, "must be host:port; use WithEndpointURL"},
+		{"missing port", GrpcExporter, "collector", nil, "must be host:port"},
+		{"empty port", HttpExporter, "collector:", nil, "must be host:port"},
+		{"empty host", GrpcExporter, ":4317", nil, "must be host:port"},
+		{"Unix socket over HTTP", HttpExporter, "unix:///run/otel.sock", nil, "only supported by the gRPC exporters"},
+		{"failover with stdout", StdoutExporter, "", []Option{WithFailoverEndpoints([]string{"backup:4317"})}, "failover endpoints require"},
+		{"SRV with none", NoneExporter, "", []Option{WithSRVEndpoint("otlp")}, "SRV endpoints require"},
+		{"unknown exporter", ExporterType(99), "", nil, "unknown exporter type"},
+		{"zero metric interval", NoneExporter, "", []Option{WithMetricExportInterval(0)}, "metric export interval must be positive"},
+		{"negative breaker", NoneExporter, "", []Option{WithCircuitBreaker(-1, time.Second)}, "circuit breaker threshold must be positive"},
+		{"zero keepalive timeout", GrpcExporter, "collector:4317", []Option{WithKeepalive(time.Minute, 0)}, "keepalive interval and timeout must be positive"},
+		{"zero attribute value length", NoneExporter, "", []Option{WithAttributeValueLengthLimit(0)}, "attribute value length limit must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithoutGlobalRegistration()}, tt.opts...)
+			p, err := Setup(context.Background(), tt.exporterType, tt.address, resource.Empty(), opts...)
+			if err == nil {
+				p.Shutdown(context.Background())
+				t.Fatal("Setup succeeded")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Setup error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePortMismatchIsNotFatal(t *testing.T) {
+	errs := recordErrors(t)
+	p, err := Setup(context.Background(), HttpExporter, "localhost:4317", resource.Empty(), WithoutGlobalRegistration())
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+
+	got := errs.Errors()
+	if len(got) != 1 || !strings.Contains(got[0].Error(), "OTLP/gRPC port 4317 with the HTTP exporter") {
+		t.Errorf("reported errors = %v, want the port mismatch", got)
+	}
+}
+
+func TestFailOpenCoversInvalidOptions(t *testing.T) {
+	errs := recordErrors(t)
+	p, err := Setup(context.Background(), GrpcExporter, "http://collector:4317", resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithFailOpen(),
+	)
+	if err != nil {
+		t.Fatalf("Setup with WithFailOpen: %v", err)
+	}
+	if p.TracerProvider != nil || p.MeterProvider != nil || p.LoggerProvider != nil {
+		t.Error("Setup returned providers for an invalid config")
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+	if len(errs.Errors()) != 1 {
+		t.Errorf("reported errors = %v, want the validation error", errs.Errors())
+	}
+}
+
+func TestTraceBasedExemplars(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p := setupTest(t, WithTraceBasedExemplars(), WithCustomMetricReader(reader))
+
+	ctx, span := p.TracerProvider.Tracer("test").Start(context.Background(), "request")
+	counter, err := p.MeterProvider.Meter("test").Int64Counter("requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(ctx, 1)
+	span.End()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	dp := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0]
+	if len(dp.Exemplars) != 1 {
+		t.Fatalf("got %d exemplars, want 1", len(dp.Exemplars))
+	}
+	traceID := span.SpanContext().TraceID()
+	if got := dp.Exemplars[0].TraceID; string(got) != string(traceID[:]) {
+		t.Errorf("exemplar trace ID = %x, want %s", got, traceID)
+	}
+}
+
+func TestLogExportInterval(t *testing.T) {
+	exp := &memLogExporter{}
+	p := setupTest(t, WithCustomLogExporter(exp), WithLogExportInterval(20*time.Millisecond))
+
+	var r log.Record
+	r.SetBody(log.StringValue("batched"))
+	p.LoggerProvider.Logger("test").Emit(context.Background(), r)
+
+	// The SDK default interval is a second; the record must be exported well
+	// before it.
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for len(exp.Records()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("record not exported within the configured interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// stuckLogExporter blocks every export until its context is done and records
+// the first context error.
+type stuckLogExporter struct {
+	memLogExporter
+	errs chan error
+}
+
+func (e *stuckLogExporter) Export(ctx context.Context, _ []sdklog.Record) error {
+	<-ctx.Done()
+	select {
+	case e.errs <- ctx.Err():
+	default:
+	}
+	return ctx.Err()
+}
+
+func TestLogBatchTimeout(t *testing.T) {
+	recordErrors(t)
+	exp := &stuckLogExporter{errs: make(chan error, 1)}
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithLogExportInterval(time.Millisecond),
+		WithLogBatchTimeout(20*time.Millisecond),
+	)
+
+	var r log.Record
+	r.SetBody(log.StringValue("stuck"))
+	p.LoggerProvider.Logger("test").Emit(context.Background(), r)
+
+	select {
+	case err := <-exp.errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("export ended with %v, want the deadline", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("export not cancelled by the batch timeout")
+	}
+}
+
+func TestFailOpenInstallsNoopProvider(t *testing.T) {
+	errs := recordErrors(t)
+	ctx := context.Background()
+	// The Zipkin exporter rejects a URL without a scheme, so only traces fail
+	// to set up.
+	p, err := Setup(ctx, ZipkinExporter, "localhost:4317", resource.Empty(),
+		WithZipkinURL("zipkin:9411"),
+		WithFailOpen(),
+	)
+	if err != nil {
+		t.Fatalf("Setup with WithFailOpen: %v", err)
+	}
+	t.Cleanup(func() {
+		// Nothing listens for the metrics and logs, so do not wait for them.
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		Reset(ctx)
+	})
+
+	if p.TracerProvider != nil {
+		t.Error("Setup returned a TracerProvider for a failed exporter")
+	}
+	if p.MeterProvider == nil || p.LoggerProvider == nil {
+		t.Error("Setup disabled the signals that did not fail")
+	}
+	_, span := otel.Tracer("test").Start(ctx, "noop")
+	defer span.End()
+	if span.IsRecording() || span.SpanContext().IsValid() {
+		t.Error("global tracer is not a noop tracer")
+	}
+	if got := errs.Errors(); len(got) != 1 || !strings.Contains(got[0].Error(), "traces") {
+		t.Errorf("reported errors = %v, want the traces setup error", got)
+	}
+}
+
+// writeKeyPair writes a self-signed certificate and its key as PEM files in a
+// temporary directory and returns their paths and the DER certificate.
+func writeKeyPair(t *testing.T) (certFile, keyFile string, der []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, der
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	certFile, keyFile, der := writeKeyPair(t)
+	cfg := newConfig(GrpcExporter, "collector:4317", resource.Empty(), []Option{
+		WithClientCertificate(certFile, keyFile),
+		WithCACertificate(certFile),
+	})
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig := cfg.transportTLS()
+	if tlsConfig == nil {
+		t.Fatal("no TLS config with a client certificate")
+	}
+	if len(tlsConfig.Certificates) != 1 || !bytes.Equal(tlsConfig.Certificates[0].Certificate[0], der) {
+		t.Error("TLS config does not hold the client certificate")
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Error("TLS config does not hold the CA certificate")
+	}
+}
+
+func TestWithClientCertificateMissingFile(t *testing.T) {
+	cfg := newConfig(GrpcExporter, "collector:4317", resource.Empty(), []Option{
+		WithClientCertificate(filepath.Join(t.TempDir(), "missing.pem"), "missing-key.pem"),
+	})
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "loading client certificate") {
+		t.Errorf("Validate error = %v, want the loading error", err)
+	}
+}
+
+func TestWithPrometheusProducer(t *testing.T) {
+	c := newMetricsCollector(t)
+	registry := prometheus.NewRegistry()
+	jobs := prometheus.NewCounter(prometheus.CounterOpts{Name: "legacy_jobs_total", Help: "Jobs run."})
+	registry.MustRegister(jobs)
+	jobs.Add(3)
+
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithInsecure(true),
+		WithPrometheusProducer(registry),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range c.find("legacy_jobs_total") {
+		if dps := m.GetSum().GetDataPoints(); len(dps) == 1 && dps[0].GetAsDouble() == 3 {
+			return
+		}
+	}
+	t.Error("the Prometheus counter is not in the OTLP metric export")
+}
+
+func TestWithEndpointURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		address   string
+		insecure  string
+		tracePath string
+		err       string
+	}{
+		{url: "http://localhost:4318", address: "localhost:4318", insecure: "true"},
+		{url: "https://otlp.example.com:4318/otlp/", address: "otlp.example.com:4318", insecure: "false", tracePath: "/otlp/v1/traces"},
+		{url: "collector:4317", address: "collector:4317", insecure: "unset"},
+		{url: "ftp://collector:21", err: "scheme must be http or https"},
+		{url: "http://", err: "invalid endpoint URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			cfg := newConfig(HttpExporter, "ignored:4318", resource.Empty(), []Option{WithEndpointURL(tt.url)})
+			err := cfg.Validate()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Validate error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.otlpAddress != tt.address {
+				t.Errorf("address = %q, want %q", cfg.otlpAddress, tt.address)
+			}
+			insecure := "unset"
+			if cfg.insecure != nil {
+				insecure = fmt.Sprint(*cfg.insecure)
+			}
+			if insecure != tt.insecure {
+				t.Errorf("insecure = %s, want %s", insecure, tt.insecure)
+			}
+			if cfg.tracesURLPath != tt.tracePath {
+				t.Errorf("traces URL path = %q, want %q", cfg.tracesURLPath, tt.tracePath)
+			}
+		})
+	}
+}
+
+func TestWithTemporalitySelector(t *testing.T) {
+	c := newMetricsCollector(t)
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithInsecure(true),
+		WithTemporalitySelector(func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			if kind == sdkmetric.InstrumentKindCounter {
+				return metricdata.DeltaTemporality
+			}
+			return metricdata.CumulativeTemporality
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter := p.MeterProvider.Meter("test")
+	requests, _ := meter.Int64Counter("requests")
+	requests.Add(ctx, 1)
+	inflight, _ := meter.Int64UpDownCounter("inflight")
+	inflight.Add(ctx, 1)
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]metricpb.AggregationTemporality{
+		"requests": metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+		"inflight": metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+	} {
+		metrics := c.find(name)
+		if len(metrics) == 0 {
+			t.Errorf("%s was not exported", name)
+			continue
+		}
+		if got := metrics[0].GetSum().GetAggregationTemporality(); got != want {
+			t.Errorf("%s temporality = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestWithExponentialHistograms(t *testing.T) {
+	c := newMetricsCollector(t)
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithInsecure(true),
+		WithExponentialHistograms(),
+		WithView(HistogramBuckets("size", []float64{1024, 4096})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter := p.MeterProvider.Meter("test")
+	latency, _ := meter.Float64Histogram("latency")
+	size, _ := meter.Int64Histogram("size")
+	for _, v := range []float64{0.5, 3, 12} {
+		latency.Record(ctx, v)
+		size.Record(ctx, int64(v*1000))
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	latencies := c.find("latency")
+	if len(latencies) == 0 {
+		t.Fatal("latency was not exported")
+	}
+	exp := latencies[0].GetExponentialHistogram()
+	if exp == nil {
+		t.Fatalf("latency = %v, want an exponential histogram", latencies[0].GetData())
+	}
+	if n := exp.GetDataPoints()[0].GetCount(); n != 3 {
+		t.Errorf("latency count = %d, want 3", n)
+	}
+	sizes := c.find("size")
+	if len(sizes) == 0 || sizes[0].GetHistogram() == nil {
+		t.Errorf("size = %v, want the explicit buckets of its view", sizes)
+	}
+}
+
+func TestWithInsecure(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []Option
+		useTLS bool
+	}{
+		{name: "default", useTLS: false},
+		{name: "insecure", opts: []Option{WithInsecure(true)}, useTLS: false},
+		{name: "secure", opts: []Option{WithInsecure(false)}, useTLS: true},
+	} {
+		cfg := newConfig(HttpExporter, "collector:4318", resource.Empty(), tt.opts)
+		tlsConfig := cfg.transportTLS()
+		if got := tlsConfig != nil; got != tt.useTLS {
+			t.Errorf("%s: uses TLS = %v, want %v", tt.name, got, tt.useTLS)
+		}
+		if tlsConfig != nil && tlsConfig.RootCAs != nil {
+			t.Errorf("%s: root CAs are set, want the system certificate pool", tt.name)
+		}
+	}
+}
+
+// tlsExports returns the number of span exports reaching a TLS collector
+// using a self-signed certificate, when Setup is called with the options
+// returned by opts for the certificate.
+func tlsExports(t *testing.T, opts func(cert *x509.Certificate) []Option) int {
+	t.Helper()
+	recordErrors(t)
+	var n atomic.Int64
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { n.Add(1) }))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, strings.TrimPrefix(srv.URL, "https://"), resource.Empty(), append([]Option{
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithRetryConfig(RetryConfig{Enabled: false}),
+		WithCustomMetricReader(sdkmetric.NewManualReader()),
+		WithCustomLogExporter(&memLogExporter{}),
+	}, opts(srv.Certificate())...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(ctx)
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "export")
+	span.End()
+	return int(n.Load())
+}
+
+func TestWithInsecureFalseVerifiesCollector(t *testing.T) {
+	// The self-signed certificate is not in the system certificate pool.
+	n := tlsExports(t, func(*x509.Certificate) []Option {
+		return []Option{WithInsecure(false)}
+	})
+	if n != 0 {
+		t.Errorf("%d exports reached an untrusted collector, want 0", n)
+	}
+
+	n = tlsExports(t, func(cert *x509.Certificate) []Option {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return []Option{WithInsecure(false), WithCACertificate(caFile)}
+	})
+	if n != 1 {
+		t.Errorf("%d exports reached a collector trusted with WithCACertificate, want 1", n)
+	}
+}
+
+func TestWithDefaultAggregation(t *testing.T) {
+	c := newMetricsCollector(t)
+	ctx := context.Background()
+	boundaries := []float64{1, 10}
+	p, err := Setup(ctx, HttpExporter, c.address(), resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithInsecure(true),
+		WithDefaultAggregation(func(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+			if k == sdkmetric.InstrumentKindHistogram {
+				return sdkmetric.AggregationExplicitBucketHistogram{Boundaries: boundaries}
+			}
+			return sdkmetric.DefaultAggregationSelector(k)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter := p.MeterProvider.Meter("test")
+	latency, _ := meter.Float64Histogram("latency")
+	latency.Record(ctx, 5)
+	requests, _ := meter.Int64Counter("requests")
+	requests.Add(ctx, 1)
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	latencies := c.find("latency")
+	if len(latencies) == 0 {
+		t.Fatal("latency was not exported")
+	}
+	if got := latencies[0].GetHistogram().GetDataPoints()[0].GetExplicitBounds(); !slices.Equal(got, boundaries) {
+		t.Errorf("latency bounds = %v, want %v", got, boundaries)
+	}
+	counters := c.find("requests")
+	if len(counters) == 0 || !counters[0].GetSum().GetIsMonotonic() {
+		t.Errorf("requests = %v, want a monotonic sum", counters)
+	}
+}
+
+func TestWithAttributeValueLengthLimit(t *testing.T) {
+	spans := &fakeSpanExporter{}
+	logs := &memLogExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(spans),
+		WithCustomLogExporter(logs),
+		WithSyncSpanProcessor(),
+		WithAttributeValueLengthLimit(8),
+	)
+	statement := "SELECT * FROM orders WHERE id = 42"
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "query")
+	span.SetAttributes(attribute.String("db.statement", statement))
+	span.End()
+	var r log.Record
+	r.AddAttributes(log.String("db.statement", statement))
+	p.LoggerProvider.Logger("test").Emit(context.Background(), r)
+
+	exported := spans.Spans()
+	if len(exported) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(exported))
+	}
+	if got := exported[0].Attributes()[0].Value.AsString(); got != "SELECT *" {
+		t.Errorf("span db.statement = %q, want %q", got, "SELECT *")
+	}
+	records := logs.Records()
+	if len(records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(records))
+	}
+	if got := recordAttr(records[0], "db.statement"); got != "SELECT *" {
+		t.Errorf("record db.statement = %q, want %q", got, "SELECT *")
+	}
+}
@@ -3,91 +3,351 @@ package telemetry
 import (
 	"context"
 	"errors"
-	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
-	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
-	"go.opentelemetry.io/otel/sdk/resource"
-	"time"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/sdk/resource"
 
 	"go.opentelemetry.io/otel/log/global"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
+// name is the instrumentation scope used for telemetry emitted by this package.
+const name = "github.com/billmeyer/go-otel-core/pkg/telemetry"
+
+// stdout is where the StdoutExporter writes, a variable so that its output can
+// be captured.
+var stdout io.Writer = os.Stdout
+
 type ExporterType int
 
 const (
 	GrpcExporter ExporterType = iota
 	HttpExporter
 	StdoutExporter
+	// NoneExporter creates real providers, so spans are sampled and processed
+	// as usual, but discards all telemetry instead of exporting it.
+	NoneExporter
+	// ZipkinExporter sends traces to a Zipkin collector, see WithZipkinURL.
+	// Metrics and logs are sent over OTLP/gRPC to otlpAddress.
+	ZipkinExporter
 )
 
 // SetupOTelSDK bootstraps the OpenTelemetry pipeline.
 // If it does not return an error, make sure to call shutdown for proper cleanup.
-func SetupOTelSDK(ctx context.Context, exporterType ExporterType, otlpAddress string, resources *resource.Resource) (shutdown func(context.Context) error, err error) {
-	var shutdownFuncs []func(context.Context) error
+func SetupOTelSDK(ctx context.Context, exporterType ExporterType, otlpAddress string, resources *resource.Resource, opts ...Option) (shutdown func(context.Context) error, err error) {
+	providers, err := Setup(ctx, exporterType, otlpAddress, resources, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return providers.Shutdown, nil
+}
 
-	// shutdown calls cleanup functions registered via shutdownFuncs.
-	// The errors from the calls are joined.
-	// Each registered cleanup will be invoked once.
-	shutdown = func(ctx context.Context) error {
-		var err error
-		for _, fn := range shutdownFuncs {
-			err = errors.Join(err, fn(ctx))
+// Providers holds the providers of a pipeline created by Setup.
+// A provider is nil when its signal was disabled by WithFailOpen.
+type Providers struct {
+	TracerProvider *sdktrace.TracerProvider
+	MeterProvider  *sdkmetric.MeterProvider
+	LoggerProvider *sdklog.LoggerProvider
+
+	// mu guards shutdownFuncs once the providers are in use.
+	mu            sync.Mutex
+	shutdownFuncs []func(context.Context) error
+	// flushFuncs flush the exporters buffering spans after the providers.
+	flushFuncs []func(context.Context) error
+}
+
+// Setup bootstraps the OpenTelemetry pipeline like SetupOTelSDK, but returns
+// the created providers.
+// If it does not return an error, make sure to call Shutdown for proper cleanup.
+func Setup(ctx context.Context, exporterType ExporterType, otlpAddress string, resources *resource.Resource, opts ...Option) (providers *Providers, err error) {
+	cfg := newConfig(exporterType, otlpAddress, resources, opts)
+	if err := cfg.Validate(); err != nil {
+		if !cfg.failOpen {
+			return nil, err
+		}
+		// No signal can be set up from an invalid config.
+		otel.Handle(fmt.Errorf("telemetry: traces, metrics and logs disabled: %w", err))
+		providers = &Providers{}
+		if cfg.registerGlobal {
+			registerGlobal(cfg, providers)
 		}
-		shutdownFuncs = nil
-		return err
+		return providers, nil
+	}
+	if err := cfg.applySRVEndpoint(ctx); err != nil {
+		return nil, err
+	}
+	providers = &Providers{}
+
+	if cfg.exporterMetrics {
+		cfg.pipelineMetrics = newPipelineMetrics(cfg)
+		providers.shutdownFuncs = append(providers.shutdownFuncs, cfg.pipelineMetrics.shutdown)
 	}
 
 	// handleErr calls shutdown for cleanup and makes sure that all errors are returned.
 	handleErr := func(inErr error) {
-		err = errors.Join(inErr, shutdown(ctx))
+		err = errors.Join(inErr, providers.Shutdown(ctx))
+		providers = nil
 	}
 
-	// Set up propagator.
-	prop := newPropagator()
-	otel.SetTextMapPropagator(prop)
+	// failOpen reports whether a signal that failed to set up should fall back
+	// to a noop provider instead of failing the whole setup.
+	failOpen := func(signal string, inErr error) bool {
+		if !cfg.failOpen {
+			return false
+		}
+		otel.Handle(fmt.Errorf("telemetry: %s disabled: %w", signal, inErr))
+		return true
+	}
 
 	// Set up trace provider.
-	tracerProvider, err := newTracerProvider(ctx, exporterType, otlpAddress, resources)
+	tracerProvider, err := buildProvider(ctx, cfg, "traces", newTracerProvider)
 	if err != nil {
-		handleErr(err)
-		return
+		if !failOpen("traces", err) {
+			handleErr(err)
+			return
+		}
+		err = nil
+	} else {
+		providers.TracerProvider = tracerProvider
+		providers.shutdownFuncs = append(providers.shutdownFuncs, drainAndShutdown(cfg, tracerProvider))
+		if cfg.spanBuffer != nil {
+			providers.flushFuncs = append(providers.flushFuncs, cfg.spanBuffer.ForceFlush)
+		}
 	}
-	shutdownFuncs = append(shutdownFuncs, tracerProvider.Shutdown)
-	otel.SetTracerProvider(tracerProvider)
 
 	// Set up meter provider.
-	meterProvider, err := newMeterProvider(ctx, exporterType, otlpAddress, resources)
+	meterProvider, err := buildProvider(ctx, cfg, "metrics", newMeterProvider)
 	if err != nil {
-		handleErr(err)
-		return
+		if !failOpen("metrics", err) {
+			handleErr(err)
+			return
+		}
+		err = nil
+	} else {
+		providers.MeterProvider = meterProvider
+		providers.shutdownFuncs = append(providers.shutdownFuncs, drainAndShutdown(cfg, meterProvider))
 	}
-	shutdownFuncs = append(shutdownFuncs, meterProvider.Shutdown)
-	otel.SetMeterProvider(meterProvider)
 
 	// Set up logger provider.
-	loggerProvider, err := newLoggerProvider(ctx, exporterType, otlpAddress, resources)
+	loggerProvider, err := buildProvider(ctx, cfg, "logs", newLoggerProvider)
 	if err != nil {
-		handleErr(err)
-		return
+		if !failOpen("logs", err) {
+			handleErr(err)
+			return
+		}
+		err = nil
+	} else {
+		providers.LoggerProvider = loggerProvider
+		providers.shutdownFuncs = append(providers.shutdownFuncs, drainAndShutdown(cfg, loggerProvider))
 	}
-	shutdownFuncs = append(shutdownFuncs, loggerProvider.Shutdown)
-	global.SetLoggerProvider(loggerProvider)
 
+	// The exporters record the pipeline's own metrics with its MeterProvider,
+	// which is only known now, rather than the global one.
+	if providers.MeterProvider != nil {
+		if err = bindSelfMetrics(cfg, providers.MeterProvider.Meter(name)); err != nil {
+			handleErr(err)
+			return
+		}
+	}
+
+	if cfg.registerGlobal {
+		registerGlobal(cfg, providers)
+	}
+	if cfg.startupSpan {
+		recordStartupSpan(ctx, cfg, providers)
+	}
 	return
 }
 
+// spanBatchOptions returns the options of the span batch processors.
+func spanBatchOptions(cfg *config) []sdktrace.BatchSpanProcessorOption {
+	opts := []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithBatchTimeout(cfg.spanScheduleDelay),
+	}
+	if cfg.spanExportTimeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(cfg.spanExportTimeout))
+	}
+	queueSize := cfg.spanQueueSize()
+	if cfg.spanMaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(queueSize))
+	}
+	// The processor exports whenever a full batch is queued, so a batch as
+	// large as the queue exports exactly when the queue fills up.
+	batchSize := cfg.spanMaxBatchSize
+	if cfg.eagerFlush {
+		batchSize = queueSize
+	}
+	if batchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(min(batchSize, queueSize)))
+	}
+	return opts
+}
+
+// provider is implemented by the providers of every signal.
+type provider interface {
+	ForceFlush(context.Context) error
+	Shutdown(context.Context) error
+}
+
+// drainAndShutdown returns a cleanup function that exports the telemetry
+// still queued by p before shutting it down, so the last batch is not lost
+// when the shutdown deadline is short. The flush is bounded by the timeout
+// set with WithDrainTimeout; p is shut down even if it fails.
+func drainAndShutdown(cfg *config, p provider) func(context.Context) error {
+	timeout := cfg.drainTimeout
+	return func(ctx context.Context) error {
+		flushCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			flushCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return errors.Join(p.ForceFlush(flushCtx), p.Shutdown(ctx))
+	}
+}
+
+// buildProvider calls build, bounding it by the timeout set with
+// WithSetupTimeout.
+func buildProvider[P any](ctx context.Context, cfg *config, signal string, build func(context.Context, *config) (P, error)) (P, error) {
+	if cfg.setupTimeout <= 0 {
+		return build(ctx, cfg)
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.setupTimeout)
+	defer cancel()
+	p, err := build(ctx, cfg)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("telemetry: setting up %s timed out after %s: %w", signal, cfg.setupTimeout, err)
+	}
+	return p, err
+}
+
+// SetupMetrics bootstraps only the metric pipeline, for programs that do not
+// emit traces or logs. The exporter, collector address and resource are set
+// with WithExporter, WithEndpoint and WithResource; they default to OTLP over
+// gRPC to localhost:4317 and resource.Default. Unless WithoutGlobalRegistration
+// is used, the meter provider is installed as the global one.
+// If it does not return an error, make sure to call shutdown for proper cleanup.
+func SetupMetrics(ctx context.Context, opts ...Option) (meterProvider *sdkmetric.MeterProvider, shutdown func(context.Context) error, err error) {
+	cfg := newConfig(GrpcExporter, "", resource.Default(), opts)
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := cfg.applySRVEndpoint(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	meterProvider, err = buildProvider(ctx, cfg, "metrics", newMeterProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shutdown = drainAndShutdown(cfg, meterProvider)
+	if cfg.registerGlobal {
+		setServiceName(cfg.resources)
+		otel.SetMeterProvider(meterProvider)
+		resetInstruments()
+		currentProviders.Store(&Providers{
+			MeterProvider: meterProvider,
+			shutdownFuncs: []func(context.Context) error{shutdown},
+		})
+	}
+	return meterProvider, shutdown, nil
+}
+
+// registerGlobal installs the propagator, the partial success handler and the
+// providers of p as the OpenTelemetry globals. Signals disabled by
+// WithFailOpen get noop providers.
+func registerGlobal(cfg *config, p *Providers) {
+	otel.SetTextMapPropagator(newPropagator())
+	installPartialSuccessHandler()
+	setServiceName(cfg.resources)
+	currentHealth.Store(cfg.health)
+	currentSampling.Store(cfg.sampling)
+	currentProviders.Store(p)
+	resetInstruments()
+
+	if p.TracerProvider != nil {
+		otel.SetTracerProvider(p.TracerProvider)
+	} else {
+		otel.SetTracerProvider(tracenoop.NewTracerProvider())
+	}
+	if p.MeterProvider != nil {
+		otel.SetMeterProvider(p.MeterProvider)
+	} else {
+		otel.SetMeterProvider(metricnoop.NewMeterProvider())
+	}
+	if p.LoggerProvider != nil {
+		global.SetLoggerProvider(p.LoggerProvider)
+	} else {
+		global.SetLoggerProvider(lognoop.NewLoggerProvider())
+	}
+}
+
+// ForceFlush exports all telemetry buffered by the providers without shutting
+// them down. Unlike Shutdown it may be called any number of times.
+// The errors from the providers are joined.
+func (p *Providers) ForceFlush(ctx context.Context) error {
+	var err error
+	if p.TracerProvider != nil {
+		err = errors.Join(err, p.TracerProvider.ForceFlush(ctx))
+	}
+	for _, fn := range p.flushFuncs {
+		err = errors.Join(err, fn(ctx))
+	}
+	if p.MeterProvider != nil {
+		err = errors.Join(err, p.MeterProvider.ForceFlush(ctx))
+	}
+	if p.LoggerProvider != nil {
+		err = errors.Join(err, p.LoggerProvider.ForceFlush(ctx))
+	}
+	return err
+}
+
+// Shutdown calls the cleanup functions of the providers, which flush each
+// provider before shutting it down.
+// The errors from the calls are joined.
+// Each registered cleanup will be invoked once.
+func (p *Providers) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	funcs := p.shutdownFuncs
+	p.shutdownFuncs = nil
+	p.mu.Unlock()
+
+	var err error
+	for _, fn := range funcs {
+		err = errors.Join(err, fn(ctx))
+	}
+	return err
+}
+
+// onShutdown makes Shutdown call fn after shutting down the providers.
+func (p *Providers) onShutdown(fn func(context.Context) error) {
+	p.mu.Lock()
+	p.shutdownFuncs = append(p.shutdownFuncs, fn)
+	p.mu.Unlock()
+}
+
 func newPropagator() propagation.TextMapPropagator {
 	return propagation.NewCompositeTextMapPropagator(
 		propagation.TraceContext{},
@@ -95,95 +355,261 @@ func newPropagator() propagation.TextMapPropagator {
 	)
 }
 
-func newTracerProvider(ctx context.Context, exporterType ExporterType, otlpAddress string, resources *resource.Resource) (*sdktrace.TracerProvider, error) {
+func newTracerProvider(ctx context.Context, cfg *config) (*sdktrace.TracerProvider, error) {
 	var err error
 	var traceExporter sdktrace.SpanExporter
 
-	switch exporterType {
-	case GrpcExporter:
-		traceExporter, err = otlptracegrpc.New(ctx,
-			otlptracegrpc.WithInsecure(),
-			otlptracegrpc.WithEndpoint(otlpAddress),
-		)
-	case HttpExporter:
-		traceExporter, err = otlptracehttp.New(ctx,
-			otlptracehttp.WithInsecure(),
-			otlptracehttp.WithEndpoint(otlpAddress),
-		)
-	case StdoutExporter:
-		traceExporter, err = stdouttrace.New(
-			stdouttrace.WithPrettyPrint())
+	switch {
+	case cfg.customTraceExporter != nil:
+		traceExporter = cfg.customTraceExporter
+	case cfg.exporterType == GrpcExporter:
+		traceExporter, err = otlptracegrpc.New(ctx, traceGRPCOptions(cfg)...)
+	case cfg.exporterType == HttpExporter:
+		traceExporter, err = otlptracehttp.New(ctx, traceHTTPOptions(cfg)...)
+	case cfg.exporterType == StdoutExporter:
+		stdoutOpts := []stdouttrace.Option{stdouttrace.WithWriter(stdout)}
+		if !cfg.stdoutCompact {
+			stdoutOpts = append(stdoutOpts, stdouttrace.WithPrettyPrint())
+		}
+		traceExporter, err = stdouttrace.New(stdoutOpts...)
+	case cfg.exporterType == NoneExporter:
+		traceExporter = discardSpanExporter{}
+	case cfg.exporterType == ZipkinExporter:
+		traceExporter, err = zipkin.New(cfg.zipkinURL)
+	}
+	if err == nil && len(cfg.failoverEndpoints) > 0 && cfg.customTraceExporter == nil {
+		traceExporter, err = newFailoverSpanExporter(ctx, cfg, traceExporter)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(traceExporter,
-			// Default is 5s. Set to 1s for demonstrative purposes.
-			sdktrace.WithBatchTimeout(time.Second)),
-		sdktrace.WithResource(resources),
-	)
+	traceExporter = healthSpanExporter{traceExporter, &cfg.health.traces}
+	if m := cfg.pipelineMetrics; m != nil {
+		traceExporter = metricsSpanExporter{traceExporter, &m.traces}
+	}
+	// queued counts the spans waiting in the batch processor of the primary
+	// exporter, for the exporter metrics and the export callback.
+	var queued *atomic.Int64
+	if m := cfg.pipelineMetrics; m != nil {
+		queued = &m.traces.queued
+	}
+	if cfg.exportCallback != nil {
+		if queued == nil {
+			queued = new(atomic.Int64)
+		}
+		traceExporter = &callbackExporter{SpanExporter: traceExporter, fn: cfg.exportCallback, queued: queued}
+	}
+	if cfg.breakerThreshold > 0 {
+		// The wrappers above report the health of the collector, so they
+		// must not see the batches the open breaker drops as exported.
+		traceExporter = newCircuitBreakerExporter(cfg, traceExporter, cfg.breakerThreshold, cfg.breakerCooldown)
+	}
+
+	if cfg.spanBufferSize > 0 {
+		cfg.spanBuffer, err = newBufferedExporter(traceExporter, cfg.spanBufferSize, cfg.spanBufferFallback, newOverflowCounter(cfg))
+		if err != nil {
+			return nil, err
+		}
+		traceExporter = cfg.spanBuffer
+	}
+
+	exporters := append([]sdktrace.SpanExporter{traceExporter}, cfg.additionalTraceExporters...)
+	var transformers []spanTransformer
+	for _, sp := range cfg.spanProcessors {
+		if t, ok := sp.(spanTransformer); ok {
+			transformers = append(transformers, t)
+		}
+	}
+	if len(transformers) > 0 {
+		for i, exp := range exporters {
+			exporters[i] = transformExporter{exp, transformers}
+		}
+	}
+
+	tracerOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(cfg.resources),
+	}
+	for i, exp := range exporters {
+		if i == 0 && queued != nil {
+			exp = dequeueSpanExporter{exp, queued}
+		}
+		var sp sdktrace.SpanProcessor
+		if cfg.syncExport {
+			sp = sdktrace.NewSimpleSpanProcessor(exp)
+		} else {
+			sp = sdktrace.NewBatchSpanProcessor(exp, spanBatchOptions(cfg)...)
+		}
+		if i == 0 && queued != nil {
+			sp = queueSpanProcessor{sp, queued, int64(cfg.spanQueueSize())}
+		}
+		tracerOpts = append(tracerOpts, sdktrace.WithSpanProcessor(sp))
+	}
+	// Wrap the SDK default when no sampler is set, so SamplerDebugHandler can
+	// count its decisions too.
+	sampler := cfg.sampler
+	if sampler == nil {
+		sampler = sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+	tracerOpts = append(tracerOpts, sdktrace.WithSampler(newStatsSampler(sampler, cfg.sampling)))
+	if cfg.idGenerator != nil {
+		tracerOpts = append(tracerOpts, sdktrace.WithIDGenerator(cfg.idGenerator))
+	}
+	if limit := cfg.attributeValueLengthLimit; limit > 0 {
+		// NewSpanLimits keeps the other limits set by the environment.
+		limits := sdktrace.NewSpanLimits()
+		limits.AttributeValueLengthLimit = limit
+		tracerOpts = append(tracerOpts, sdktrace.WithSpanLimits(limits))
+	}
+	for _, sp := range cfg.spanProcessors {
+		tracerOpts = append(tracerOpts, sdktrace.WithSpanProcessor(sp))
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(tracerOpts...)
 	return tracerProvider, nil
 }
 
-func newMeterProvider(ctx context.Context, exporterType ExporterType, otlpAddress string, resources *resource.Resource) (*sdkmetric.MeterProvider, error) {
+func newMeterProvider(ctx context.Context, cfg *config) (*sdkmetric.MeterProvider, error) {
 	var err error
 	var metricExporter sdkmetric.Exporter
 
-	switch exporterType {
-	case GrpcExporter:
-		metricExporter, err = otlpmetricgrpc.New(ctx,
-			otlpmetricgrpc.WithInsecure(),
-			otlpmetricgrpc.WithEndpoint(otlpAddress))
-	case HttpExporter:
-		metricExporter, err = otlpmetrichttp.New(ctx,
-			otlpmetrichttp.WithInsecure(),
-			otlpmetrichttp.WithEndpoint(otlpAddress))
-	case StdoutExporter:
-		metricExporter, err = stdoutmetric.New()
+	switch {
+	case cfg.customMetricReader != nil:
+		// The reader replaces the exporter and its periodic reader.
+	case cfg.exporterType == GrpcExporter || cfg.exporterType == ZipkinExporter:
+		metricExporter, err = otlpmetricgrpc.New(ctx, metricGRPCOptions(cfg)...)
+	case cfg.exporterType == HttpExporter:
+		metricExporter, err = otlpmetrichttp.New(ctx, metricHTTPOptions(cfg)...)
+	case cfg.exporterType == StdoutExporter:
+		stdoutOpts := []stdoutmetric.Option{stdoutmetric.WithWriter(stdout)}
+		if !cfg.stdoutCompact {
+			stdoutOpts = append(stdoutOpts, stdoutmetric.WithPrettyPrint())
+		}
+		if cfg.temporalitySelector != nil {
+			stdoutOpts = append(stdoutOpts, stdoutmetric.WithTemporalitySelector(cfg.temporalitySelector))
+		}
+		if cfg.aggregationSelector != nil {
+			stdoutOpts = append(stdoutOpts, stdoutmetric.WithAggregationSelector(cfg.aggregationSelector))
+		}
+		metricExporter, err = stdoutmetric.New(stdoutOpts...)
+	case cfg.exporterType == NoneExporter:
+		metricExporter = discardMetricExporter{}
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter,
-			// Default is 1m. Set to 3s for demonstrative purposes.
-			sdkmetric.WithInterval(3*time.Second))),
-		sdkmetric.WithResource(resources),
-	)
+	exporters := cfg.additionalMetricExporters
+	if metricExporter != nil {
+		metricExporter = healthMetricExporter{metricExporter, &cfg.health.metrics}
+		if m := cfg.pipelineMetrics; m != nil {
+			metricExporter = metricsMetricExporter{metricExporter, &m.metrics}
+		}
+		exporters = append([]sdkmetric.Exporter{metricExporter}, exporters...)
+	}
+
+	meterOpts := []sdkmetric.Option{
+		sdkmetric.WithResource(cfg.resources),
+	}
+	if cfg.customMetricReader != nil {
+		meterOpts = append(meterOpts, sdkmetric.WithReader(cfg.customMetricReader))
+	}
+	readerOpts := []sdkmetric.PeriodicReaderOption{
+		sdkmetric.WithInterval(cfg.metricExportInterval),
+	}
+	for _, producer := range cfg.metricProducers {
+		readerOpts = append(readerOpts, sdkmetric.WithProducer(producer))
+	}
+	for _, exp := range exporters {
+		if cfg.deltaToCumulative {
+			exp = newCumulativeExporter(exp)
+		}
+		meterOpts = append(meterOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp, readerOpts...)))
+	}
+	if cfg.cardinalityLimit > 0 {
+		meterOpts = append(meterOpts, sdkmetric.WithCardinalityLimit(cfg.cardinalityLimit))
+	}
+	if cfg.exemplarFilter != nil {
+		meterOpts = append(meterOpts, sdkmetric.WithExemplarFilter(cfg.exemplarFilter))
+	}
+	if len(cfg.views) > 0 {
+		meterOpts = append(meterOpts, sdkmetric.WithView(cfg.views...))
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(meterOpts...)
 	return meterProvider, nil
 }
 
-func newLoggerProvider(ctx context.Context, exporterType ExporterType, otlpAddress string, resources *resource.Resource) (*sdklog.LoggerProvider, error) {
+func newLoggerProvider(ctx context.Context, cfg *config) (*sdklog.LoggerProvider, error) {
 	var err error
 	var logExporter sdklog.Exporter
 
-	switch exporterType {
-	case GrpcExporter:
-		logExporter, err = otlploggrpc.New(nil,
-			otlploggrpc.WithInsecure(),
-			otlploggrpc.WithEndpoint(otlpAddress),
-		)
-	case HttpExporter:
-		logExporter, err = otlploghttp.New(nil,
-			otlploghttp.WithInsecure(),
-			otlploghttp.WithEndpoint(otlpAddress),
-		)
-	case StdoutExporter:
-		logExporter, err = stdoutlog.New()
+	switch {
+	case cfg.customLogExporter != nil:
+		logExporter = cfg.customLogExporter
+	case cfg.exporterType == GrpcExporter || cfg.exporterType == ZipkinExporter:
+		logExporter, err = otlploggrpc.New(ctx, logGRPCOptions(cfg)...)
+	case cfg.exporterType == HttpExporter:
+		logExporter, err = otlploghttp.New(ctx, logHTTPOptions(cfg)...)
+	case cfg.exporterType == StdoutExporter:
+		stdoutOpts := []stdoutlog.Option{stdoutlog.WithWriter(stdout)}
+		if !cfg.stdoutCompact {
+			stdoutOpts = append(stdoutOpts, stdoutlog.WithPrettyPrint())
+		}
+		logExporter, err = stdoutlog.New(stdoutOpts...)
+	case cfg.exporterType == NoneExporter:
+		logExporter = discardLogExporter{}
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	loggerProvider := sdklog.NewLoggerProvider(
-		sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter)),
-		sdklog.WithResource(resources),
-	)
+	if cfg.logFallback != nil {
+		logExporter = &fallbackLogExporter{Exporter: logExporter, w: cfg.logFallback}
+	}
+	logExporter = healthLogExporter{logExporter, &cfg.health.logs}
+	if m := cfg.pipelineMetrics; m != nil {
+		logExporter = metricsLogExporter{logExporter, &m.logs}
+	}
+
+	loggerOpts := []sdklog.LoggerProviderOption{
+		sdklog.WithResource(cfg.resources),
+	}
+	if limit := cfg.attributeValueLengthLimit; limit > 0 {
+		loggerOpts = append(loggerOpts, sdklog.WithAttributeValueLengthLimit(limit))
+	}
+	// Processors are invoked in registration order and share the record, so
+	// the exporting processors must come last to see their changes.
+	var filters []logFilter
+	for _, lp := range cfg.logProcessors {
+		loggerOpts = append(loggerOpts, sdklog.WithProcessor(lp))
+		if f, ok := lp.(logFilter); ok {
+			filters = append(filters, f)
+		}
+	}
+	for i, exp := range append([]sdklog.Exporter{logExporter}, cfg.additionalLogExporters...) {
+		m := cfg.pipelineMetrics
+		if i == 0 && m != nil {
+			exp = dequeueLogExporter{exp, &m.logs.queued}
+		}
+		var logProcessor sdklog.Processor
+		if cfg.syncExport {
+			logProcessor = sdklog.NewSimpleProcessor(exp)
+		} else {
+			logProcessor = sdklog.NewBatchProcessor(exp, cfg.logBatchOpts...)
+		}
+		if i == 0 && m != nil {
+			logProcessor = queueLogProcessor{logProcessor, &m.logs.queued, int64(cfg.logQueueSize())}
+		}
+		if len(filters) > 0 {
+			logProcessor = filteredProcessor{logProcessor, filters}
+		}
+		loggerOpts = append(loggerOpts, sdklog.WithProcessor(logProcessor))
+	}
+
+	loggerProvider := sdklog.NewLoggerProvider(loggerOpts...)
 	return loggerProvider, nil
 }
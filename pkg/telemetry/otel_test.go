@@ -0,0 +1,373 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStdoutCompact(t *testing.T) {
+	out := captureStdout(func() {
+		ctx := context.Background()
+		p, err := Setup(ctx, StdoutExporter, "", resource.Empty(),
+			WithoutGlobalRegistration(),
+			WithSyncSpanProcessor(),
+			WithStdoutCompact(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracer := p.TracerProvider.Tracer("test")
+		for _, name := range []string{"first", "second"} {
+			_, span := tracer.Start(ctx, name)
+			span.End()
+		}
+		if err := p.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// The metric and log exporters write their own lines at shutdown.
+	var names []string
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var span struct {
+			Name        string
+			SpanContext any
+		}
+		if err := json.Unmarshal([]byte(line), &span); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", line, err)
+		}
+		if span.SpanContext != nil {
+			names = append(names, span.Name)
+		}
+	}
+	if len(names) != 2 || names[0] != "first" || names[1] != "second" {
+		t.Errorf("wrote spans %q on separate lines, want [first second]:\n%s", names, out)
+	}
+}
+
+// fixedIDGenerator returns the same trace and span IDs every time.
+type fixedIDGenerator struct {
+	traceID trace.TraceID
+	spanID  trace.SpanID
+}
+
+func (g fixedIDGenerator) NewIDs(context.Context) (trace.TraceID, trace.SpanID) {
+	return g.traceID, g.spanID
+}
+
+func (g fixedIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return g.spanID
+}
+
+func TestIDGenerator(t *testing.T) {
+	gen := fixedIDGenerator{
+		traceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		spanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	}
+	p := setupTest(t, WithIDGenerator(gen))
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "fixed")
+	defer span.End()
+	sc := span.SpanContext()
+	if got := sc.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want 4bf92f3577b34da6a3ce929d0e0e4736", got)
+	}
+	if got := sc.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("span ID = %s, want 00f067aa0ba902b7", got)
+	}
+}
+
+func TestProvidersForceFlush(t *testing.T) {
+	spans := &fakeSpanExporter{}
+	logs := &memLogExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(spans),
+		WithCustomLogExporter(logs),
+		WithLogExportInterval(time.Hour),
+	)
+	ctx := context.Background()
+
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "buffered")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("buffered"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+	if got := len(spans.Spans()); got != 0 {
+		t.Fatalf("exported %d spans before the batch timeout, want 0", got)
+	}
+
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(spans.Spans()); got != 1 {
+		t.Errorf("exported %d spans after ForceFlush, want 1", got)
+	}
+	if got := len(logs.Records()); got != 1 {
+		t.Errorf("exported %d log records after ForceFlush, want 1", got)
+	}
+
+	// The providers keep working after a flush.
+	_, span = p.TracerProvider.Tracer("test").Start(ctx, "after")
+	span.End()
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(spans.Spans()); got != 2 {
+		t.Errorf("exported %d spans after the second ForceFlush, want 2", got)
+	}
+}
+
+func TestSyncSpanProcessor(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "sync")
+	span.End()
+	if spans := exp.Spans(); len(spans) != 1 || spans[0].Name() != "sync" {
+		t.Errorf("exported %v right after End, want the span", spans)
+	}
+}
+
+func TestAdditionalExporters(t *testing.T) {
+	primary, second := &fakeSpanExporter{}, &fakeSpanExporter{}
+	logs, secondLogs := &memLogExporter{}, &memLogExporter{}
+	metrics := &lastMetricExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(primary),
+		WithAdditionalTraceExporter(second),
+		WithCustomLogExporter(logs),
+		WithAdditionalLogExporter(secondLogs),
+		WithAdditionalMetricExporter(metrics),
+	)
+	ctx := context.Background()
+
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "fan-out")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("fan-out"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+	counter, _ := p.MeterProvider.Meter("test").Int64Counter("fan-out")
+	counter.Add(ctx, 1)
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, exp := range []*fakeSpanExporter{primary, second} {
+		if got := len(exp.Spans()); got != 1 {
+			t.Errorf("span exporter %d received %d spans, want 1", i, got)
+		}
+	}
+	for i, exp := range []*memLogExporter{logs, secondLogs} {
+		if got := len(exp.Records()); got != 1 {
+			t.Errorf("log exporter %d received %d records, want 1", i, got)
+		}
+	}
+	if metrics.last == nil || len(metrics.last.ScopeMetrics) == 0 {
+		t.Error("additional metric exporter received no metrics")
+	}
+}
+
+func TestWithoutGlobalRegistration(t *testing.T) {
+	// The propagator and error handler installed by an earlier Setup cannot
+	// be compared, so install ones that can.
+	handler := recordErrors(t)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+	tp, mp, lp := otel.GetTracerProvider(), otel.GetMeterProvider(), global.GetLoggerProvider()
+
+	setupTest(t)
+
+	if otel.GetTracerProvider() != tp || otel.GetMeterProvider() != mp || global.GetLoggerProvider() != lp {
+		t.Error("Setup replaced a global provider")
+	}
+	if otel.GetTextMapPropagator() != (propagation.TraceContext{}) {
+		t.Error("Setup replaced the global propagator")
+	}
+	if otel.GetErrorHandler() != otel.ErrorHandler(handler) {
+		t.Error("Setup replaced the global error handler")
+	}
+}
+
+func TestNoneExporter(t *testing.T) {
+	out := captureStdout(func() {
+		p := setupTest(t)
+		ctx := context.Background()
+		_, span := p.TracerProvider.Tracer("test").Start(ctx, "discarded")
+		if !span.IsRecording() || !span.SpanContext().IsSampled() {
+			t.Error("span is not recorded and sampled")
+		}
+		span.End()
+		if err := p.ForceFlush(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "" {
+		t.Errorf("NoneExporter wrote %q", out)
+	}
+}
+
+func TestSetupMetrics(t *testing.T) {
+	tp, lp := otel.GetTracerProvider(), global.GetLoggerProvider()
+	ctx := context.Background()
+	mp, shutdown, err := SetupMetrics(ctx, WithExporter(NoneExporter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := Reset(ctx); err != nil {
+			t.Errorf("Reset: %v", err)
+		}
+	})
+
+	if mp == nil || shutdown == nil {
+		t.Fatal("SetupMetrics returned no meter provider or shutdown")
+	}
+	if otel.GetMeterProvider() != mp {
+		t.Error("the meter provider is not the global one")
+	}
+	if otel.GetTracerProvider() != tp || global.GetLoggerProvider() != lp {
+		t.Error("SetupMetrics replaced the global tracer or logger provider")
+	}
+}
+
+func TestSetupTimeout(t *testing.T) {
+	cfg := &config{setupTimeout: 20 * time.Millisecond}
+	slow := func(ctx context.Context, _ *config) (struct{}, error) {
+		select {
+		case <-ctx.Done():
+			return struct{}{}, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return struct{}{}, nil
+		}
+	}
+
+	start := time.Now()
+	_, err := buildProvider(context.Background(), cfg, "metrics", slow)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("slow builder returned after %s, want the 20ms timeout", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "setting up metrics timed out after 20ms") {
+		t.Errorf("buildProvider error = %v, want a metrics timeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("buildProvider error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestEagerFlush(t *testing.T) {
+	// Only a full queue can trigger an export before the test ends.
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "3600000")
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSpanMaxQueueSize(4),
+		WithSpanMaxExportBatchSize(1),
+		WithEagerFlush(),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+	end := func(n int) {
+		for range n {
+			_, span := tracer.Start(context.Background(), "burst")
+			span.End()
+		}
+	}
+
+	end(3)
+	time.Sleep(20 * time.Millisecond)
+	if exp.Calls() > 0 {
+		t.Fatalf("exported %d spans before the queue was full", len(exp.Spans()))
+	}
+	end(1)
+	if !waitFor(func() bool { return len(exp.Spans()) == 4 }) {
+		t.Fatalf("exported %d spans once the queue was full, want 4", len(exp.Spans()))
+	}
+	if got := exp.Calls(); got != 1 {
+		t.Errorf("exported in %d batches, want 1", got)
+	}
+}
+
+func BenchmarkEagerFlush(b *testing.B) {
+	for _, eager := range []bool{false, true} {
+		b.Run(fmt.Sprintf("eager=%v", eager), func(b *testing.B) {
+			opts := []Option{
+				WithoutGlobalRegistration(),
+				WithCustomTraceExporter(discardSpanExporter{}),
+				WithSpanMaxQueueSize(256),
+				WithSpanMaxExportBatchSize(32),
+			}
+			if eager {
+				opts = append(opts, WithEagerFlush())
+			}
+			p, err := Setup(context.Background(), NoneExporter, "", resource.Empty(), opts...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer p.Shutdown(context.Background())
+			tracer := p.TracerProvider.Tracer("bench")
+
+			b.ReportAllocs()
+			for range b.N {
+				_, span := tracer.Start(context.Background(), "burst")
+				span.End()
+			}
+		})
+	}
+}
+
+func TestShutdownDrainsQueue(t *testing.T) {
+	spans, logs := &fakeSpanExporter{}, &memLogExporter{}
+	ctx := context.Background()
+	p, err := Setup(ctx, NoneExporter, "", resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithCustomTraceExporter(spans),
+		WithCustomLogExporter(logs),
+		WithLogExportInterval(time.Hour),
+		WithDrainTimeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The spans wait for the default one second schedule delay.
+	for range 3 {
+		_, span := p.TracerProvider.Tracer("test").Start(ctx, "last")
+		span.End()
+	}
+	var r log.Record
+	r.SetBody(log.StringValue("last"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(spans.Spans()); got != 3 {
+		t.Errorf("exported %d spans queued before shutdown, want 3", got)
+	}
+	if got := len(logs.Records()); got != 1 {
+		t.Errorf("exported %d records queued before shutdown, want 1", got)
+	}
+}
@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log/global"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// panicFlushTimeout bounds how long InstallPanicHandler waits for telemetry to
+// be exported before letting the panic continue.
+const panicFlushTimeout = 5 * time.Second
+
+// InstallPanicHandler records an unrecovered panic as a span, flushes the
+// global providers and then re-panics with the original value.
+// It must be deferred directly at the top of main (or the goroutine to guard):
+//
+//	defer telemetry.InstallPanicHandler()
+func InstallPanicHandler() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	_, span := otel.Tracer(name).Start(context.Background(), "panic")
+	recordPanic(span, r, debug.Stack())
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), panicFlushTimeout)
+	defer cancel()
+	forceFlushGlobals(ctx)
+
+	panic(r)
+}
+
+// recordPanic records the panic value r as an exception event with its stack
+// trace on span and sets the span status to Error.
+func recordPanic(span trace.Span, r any, stack []byte) {
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", r)),
+		semconv.ExceptionMessage(fmt.Sprint(r)),
+		semconv.ExceptionStacktrace(string(stack)),
+	))
+	span.SetStatus(codes.Error, fmt.Sprint(r))
+}
+
+// forceFlushGlobals flushes the global providers that support it.
+func forceFlushGlobals(ctx context.Context) {
+	type flusher interface {
+		ForceFlush(context.Context) error
+	}
+	for _, p := range []any{otel.GetTracerProvider(), otel.GetMeterProvider(), global.GetLoggerProvider()} {
+		if f, ok := p.(flusher); ok {
+			if err := f.ForceFlush(ctx); err != nil {
+				otel.Handle(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package telemetry
+
+import (
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+)
+
+// PartialSuccess describes telemetry a collector accepted only in part.
+type PartialSuccess struct {
+	// Rejected is the number of items the collector rejected.
+	Rejected int64
+	// Kind names the rejected items, such as "spans" or "log records".
+	Kind string
+	// Message is the explanation sent by the collector, if any.
+	Message string
+}
+
+// ParsePartialSuccess reports whether err is, or wraps, the partial success
+// error an OTLP exporter passes to the global error handler, and returns its
+// details.
+func ParsePartialSuccess(err error) (PartialSuccess, bool) {
+	if err == nil {
+		return PartialSuccess{}, false
+	}
+	if ps, ok := asExporterPartialSuccess(err); ok {
+		return ps, true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return ParsePartialSuccess(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if ps, ok := ParsePartialSuccess(e); ok {
+				return ps, true
+			}
+		}
+	}
+	return PartialSuccess{}, false
+}
+
+// otlpExportersPath is the import path prefix of the OTLP exporters.
+const otlpExportersPath = "go.opentelemetry.io/otel/exporters/otlp/"
+
+// asExporterPartialSuccess converts the PartialSuccess error of an OTLP trace
+// or metric exporter. Each exporter declares its own type in an internal
+// package, so errors.As cannot name them and they are matched by reflection.
+// The log exporters report plain errors, which are matched by their fixed
+// format instead.
+func asExporterPartialSuccess(err error) (PartialSuccess, bool) {
+	v := reflect.ValueOf(err)
+	t := v.Type()
+	if t.Kind() == reflect.Struct && t.Name() == "PartialSuccess" && strings.HasPrefix(t.PkgPath(), otlpExportersPath) {
+		rejected, kind, msg := v.FieldByName("RejectedItems"), v.FieldByName("RejectedKind"), v.FieldByName("ErrorMessage")
+		if rejected.Kind() == reflect.Int64 && kind.Kind() == reflect.String && msg.Kind() == reflect.String {
+			return PartialSuccess{Rejected: rejected.Int(), Kind: kind.String(), Message: msg.String()}, true
+		}
+		return PartialSuccess{}, false
+	}
+	// otlploggrpc and otlploghttp: "OTLP partial success: %s (%d log records rejected)".
+	rest, ok := strings.CutPrefix(err.Error(), "OTLP partial success: ")
+	if !ok {
+		return PartialSuccess{}, false
+	}
+	rest, ok = strings.CutSuffix(rest, " log records rejected)")
+	if !ok {
+		return PartialSuccess{}, false
+	}
+	i := strings.LastIndex(rest, " (")
+	if i < 0 {
+		return PartialSuccess{}, false
+	}
+	n, convErr := strconv.ParseInt(rest[i+2:], 10, 64)
+	if convErr != nil {
+		return PartialSuccess{}, false
+	}
+	return PartialSuccess{Rejected: n, Kind: "log records", Message: rest[:i]}, true
+}
+
+type partialSuccessHandler struct {
+	fn   func(PartialSuccess)
+	next otel.ErrorHandler
+}
+
+// NewPartialSuccessHandler returns an error handler, to install with
+// otel.SetErrorHandler, calling fn with the partial success responses of the
+// collector and passing every other error to next. A nil fn logs a warning
+// with the rejected count and message; a nil next logs the error like the
+// default handler. next must not be the result of otel.GetErrorHandler, which
+// delegates back to the installed handler.
+//
+// Setup installs such a handler with a nil fn around the current one when it
+// registers the global pipeline; use this to report partial successes with an
+// error handler set afterwards.
+func NewPartialSuccessHandler(fn func(PartialSuccess), next otel.ErrorHandler) otel.ErrorHandler {
+	if fn == nil {
+		fn = func(ps PartialSuccess) {
+			log.Printf("telemetry: warning: collector rejected %d %s: %s", ps.Rejected, ps.Kind, ps.Message)
+		}
+	}
+	return partialSuccessHandler{fn: fn, next: next}
+}
+
+func (h partialSuccessHandler) Handle(err error) {
+	if ps, ok := ParsePartialSuccess(err); ok {
+		h.fn(ps)
+		return
+	}
+	if h.next != nil {
+		h.next.Handle(err)
+		return
+	}
+	log.Print(err)
+}
+
+// installPartialSuccessHandler makes the global error handler report partial
+// successes like NewPartialSuccessHandler with a nil fn, passing every other
+// error to the handler installed before.
+func installPartialSuccessHandler() {
+	prev := otel.GetErrorHandler()
+	if _, ok := prev.(partialSuccessHandler); ok {
+		return
+	}
+	// Until a handler is set, GetErrorHandler returns the SDK's default, which
+	// delegates to the first handler set and so would call back into ours.
+	if t := reflect.TypeOf(prev); t.Kind() == reflect.Pointer && t.Elem().PkgPath() == "go.opentelemetry.io/otel/internal/global" {
+		prev = nil
+	}
+	otel.SetErrorHandler(NewPartialSuccessHandler(nil, prev))
+}
@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	collogpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// partialCollector is an OTLP/HTTP server rejecting part of every export of
+// spans and log records.
+func partialCollector(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp proto.Message
+		switch r.URL.Path {
+		case "/v1/traces":
+			resp = &coltracepb.ExportTraceServiceResponse{PartialSuccess: &coltracepb.ExportTracePartialSuccess{
+				RejectedSpans: 2,
+				ErrorMessage:  "spans too large",
+			}}
+		case "/v1/logs":
+			resp = &collogpb.ExportLogsServiceResponse{PartialSuccess: &collogpb.ExportLogsPartialSuccess{
+				RejectedLogRecords: 1,
+				ErrorMessage:       "body (too) long",
+			}}
+		default:
+			return
+		}
+		body, err := proto.Marshal(resp)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestPartialSuccessHandler(t *testing.T) {
+	address := partialCollector(t)
+	var mu sync.Mutex
+	var got []PartialSuccess
+	prev := otel.GetErrorHandler()
+	otel.SetErrorHandler(NewPartialSuccessHandler(func(ps PartialSuccess) {
+		mu.Lock()
+		got = append(got, ps)
+		mu.Unlock()
+	}, nil))
+	t.Cleanup(func() { otel.SetErrorHandler(prev) })
+
+	ctx := context.Background()
+	p, err := Setup(ctx, HttpExporter, address, resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(ctx)
+
+	_, span := p.TracerProvider.Tracer("test").Start(ctx, "export")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("export"))
+	p.LoggerProvider.Logger("test").Emit(ctx, r)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []PartialSuccess{
+		{Rejected: 2, Kind: "spans", Message: "spans too large"},
+		{Rejected: 1, Kind: "log records", Message: "body (too) long"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("partial successes = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePartialSuccess(t *testing.T) {
+	if _, ok := ParsePartialSuccess(nil); ok {
+		t.Error("ParsePartialSuccess(nil) reported a partial success")
+	}
+	if _, ok := ParsePartialSuccess(errors.New("OTLP partial success: made up (3 spans rejected)")); ok {
+		t.Error("a plain error mimicking a trace partial success was parsed")
+	}
+	logErr := errors.New("OTLP partial success: dropped (4 log records rejected)")
+	ps, ok := ParsePartialSuccess(fmt.Errorf("export: %w", errors.Join(errors.New("other"), logErr)))
+	if !ok || ps != (PartialSuccess{Rejected: 4, Kind: "log records", Message: "dropped"}) {
+		t.Errorf("ParsePartialSuccess of a wrapped error = %+v, %v", ps, ok)
+	}
+}
+
+func TestSetupInstallsPartialSuccessHandler(t *testing.T) {
+	errs := recordErrors(t)
+	setupGlobalTest(t)
+
+	if _, ok := otel.GetErrorHandler().(partialSuccessHandler); !ok {
+		t.Fatalf("global error handler is %T, want the partial success handler", otel.GetErrorHandler())
+	}
+	// Other errors still reach the handler installed before Setup.
+	otel.Handle(errors.New("boom"))
+	if got := errs.Errors(); len(got) != 1 || got[0].Error() != "boom" {
+		t.Errorf("previous handler received %v, want boom", got)
+	}
+}
@@ -0,0 +1,219 @@
+package telemetry
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// inheritProcessor copies a fixed set of attributes from a parent span onto
+// each of its children when they start.
+type inheritProcessor struct {
+	keys map[attribute.Key]struct{}
+}
+
+// NewAttributeInheritanceProcessor returns a SpanProcessor that copies the
+// attributes named by keys from the parent span found in the start context
+// onto every new child span, unless the child was started with its own value
+// for the attribute. Only parents created in this process carry
+// attributes; remote parents are ignored.
+func NewAttributeInheritanceProcessor(keys ...attribute.Key) sdktrace.SpanProcessor {
+	p := &inheritProcessor{keys: make(map[attribute.Key]struct{}, len(keys))}
+	for _, k := range keys {
+		p.keys[k] = struct{}{}
+	}
+	return p
+}
+
+func (p *inheritProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	ro, ok := trace.SpanFromContext(parent).(sdktrace.ReadOnlySpan)
+	if !ok {
+		return
+	}
+
+	// Attributes the child was started with take precedence.
+	own := make(map[attribute.Key]struct{})
+	for _, kv := range s.Attributes() {
+		own[kv.Key] = struct{}{}
+	}
+
+	var inherited []attribute.KeyValue
+	for _, kv := range ro.Attributes() {
+		if _, ok := p.keys[kv.Key]; !ok {
+			continue
+		}
+		if _, ok := own[kv.Key]; !ok {
+			inherited = append(inherited, kv)
+		}
+	}
+	if len(inherited) > 0 {
+		s.SetAttributes(inherited...)
+	}
+}
+
+func (p *inheritProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (p *inheritProcessor) Shutdown(context.Context) error   { return nil }
+func (p *inheritProcessor) ForceFlush(context.Context) error { return nil }
+
+// dynamicAttributesProcessor stamps attributes computed from the start context
+// onto every span.
+type dynamicAttributesProcessor struct {
+	fn func(context.Context) []attribute.KeyValue
+}
+
+func (p dynamicAttributesProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if attrs := p.fn(parent); len(attrs) > 0 {
+		s.SetAttributes(attrs...)
+	}
+}
+
+func (p dynamicAttributesProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (p dynamicAttributesProcessor) Shutdown(context.Context) error   { return nil }
+func (p dynamicAttributesProcessor) ForceFlush(context.Context) error { return nil }
+
+// defaultAttributesProcessor sets a fixed set of attributes on every span
+// that was not started with its own value for them.
+type defaultAttributesProcessor struct {
+	attrs []attribute.KeyValue
+}
+
+func (p defaultAttributesProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	own := make(map[attribute.Key]struct{})
+	for _, kv := range s.Attributes() {
+		own[kv.Key] = struct{}{}
+	}
+	var defaults []attribute.KeyValue
+	for _, kv := range p.attrs {
+		if _, ok := own[kv.Key]; !ok {
+			defaults = append(defaults, kv)
+		}
+	}
+	if len(defaults) > 0 {
+		s.SetAttributes(defaults...)
+	}
+}
+
+func (p defaultAttributesProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (p defaultAttributesProcessor) Shutdown(context.Context) error   { return nil }
+func (p defaultAttributesProcessor) ForceFlush(context.Context) error { return nil }
+
+// baggageProcessor copies baggage members of the start context onto spans.
+type baggageProcessor struct {
+	keys []string
+}
+
+func (p baggageProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	bag := baggage.FromContext(parent)
+	var attrs []attribute.KeyValue
+	if len(p.keys) == 0 {
+		for _, m := range bag.Members() {
+			attrs = append(attrs, attribute.String(m.Key(), m.Value()))
+		}
+	} else {
+		for _, k := range p.keys {
+			if m := bag.Member(k); m.Key() != "" {
+				attrs = append(attrs, attribute.String(k, m.Value()))
+			}
+		}
+	}
+	if len(attrs) > 0 {
+		s.SetAttributes(attrs...)
+	}
+}
+
+func (p baggageProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (p baggageProcessor) Shutdown(context.Context) error   { return nil }
+func (p baggageProcessor) ForceFlush(context.Context) error { return nil }
+
+// codeProcessor records the function that started each span.
+type codeProcessor struct{}
+
+// codeSkipPrefixes are the packages whose frames are skipped when looking for
+// the caller that started a span.
+var codeSkipPrefixes = []string{
+	"runtime.",
+	"go.opentelemetry.io/",
+	name + ".",
+}
+
+func (codeProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !skipFrame(f.Function) {
+			namespace, function := splitFuncName(f.Function)
+			s.SetAttributes(
+				semconv.CodeNamespace(namespace),
+				semconv.CodeFunction(function),
+				semconv.CodeFilepath(f.File),
+				semconv.CodeLineNumber(f.Line),
+			)
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+func skipFrame(function string) bool {
+	for _, p := range codeSkipPrefixes {
+		if strings.HasPrefix(function, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFuncName splits a fully qualified function name such as
+// example.com/pkg.(*T).Method into its namespace, example.com/pkg.(*T), and
+// its name, Method.
+func splitFuncName(fn string) (namespace, function string) {
+	i := strings.LastIndexByte(fn, '.')
+	if i < 0 {
+		return "", fn
+	}
+	return fn[:i], fn[i+1:]
+}
+
+func (codeProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (codeProcessor) Shutdown(context.Context) error   { return nil }
+func (codeProcessor) ForceFlush(context.Context) error { return nil }
+
+// slowSpanProcessor tags spans lasting longer than threshold before export.
+type slowSpanProcessor struct {
+	threshold time.Duration
+}
+
+// slowSpan is a span tagged by slowSpanProcessor.
+type slowSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+}
+
+func (s slowSpan) Attributes() []attribute.KeyValue { return s.attrs }
+func (s slowSpan) Events() []sdktrace.Event         { return s.events }
+
+func (p slowSpanProcessor) transform(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	if s.EndTime().Sub(s.StartTime()) <= p.threshold {
+		return s
+	}
+	attrs := append(append([]attribute.KeyValue(nil), s.Attributes()...), attribute.Bool("slow", true))
+	events := append(append([]sdktrace.Event(nil), s.Events()...), sdktrace.Event{Name: "slow", Time: s.EndTime()})
+	return slowSpan{ReadOnlySpan: s, attrs: attrs, events: events}
+}
+
+func (p slowSpanProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (p slowSpanProcessor) OnEnd(sdktrace.ReadOnlySpan)                     {}
+func (p slowSpanProcessor) Shutdown(context.Context) error                  { return nil }
+func (p slowSpanProcessor) ForceFlush(context.Context) error                { return nil }
@@ -0,0 +1,240 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stampProcessor sets team=payments on every span it sees start.
+type stampProcessor struct{}
+
+func (stampProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	s.SetAttributes(attribute.String("team", "payments"))
+}
+func (stampProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (stampProcessor) Shutdown(context.Context) error   { return nil }
+func (stampProcessor) ForceFlush(context.Context) error { return nil }
+
+func TestWithSpanProcessor(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithSpanProcessor(stampProcessor{}),
+	)
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "stamped")
+	span.End()
+
+	spans := exp.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	if !hasKey(spans[0].Attributes(), "team") {
+		t.Errorf("span attributes = %v, want team", spans[0].Attributes())
+	}
+}
+
+// regionLogProcessor adds region=eu-west-1 to every log record.
+type regionLogProcessor struct{}
+
+func (regionLogProcessor) OnEmit(_ context.Context, r *sdklog.Record) error {
+	r.AddAttributes(log.String("region", "eu-west-1"))
+	return nil
+}
+func (regionLogProcessor) Shutdown(context.Context) error   { return nil }
+func (regionLogProcessor) ForceFlush(context.Context) error { return nil }
+
+func TestWithLogProcessor(t *testing.T) {
+	exp := &memLogExporter{}
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithSyncSpanProcessor(),
+		WithLogProcessor(regionLogProcessor{}),
+	)
+
+	var r log.Record
+	r.SetBody(log.StringValue("enriched"))
+	p.LoggerProvider.Logger("test").Emit(context.Background(), r)
+
+	records := exp.Records()
+	if len(records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(records))
+	}
+	var region string
+	records[0].WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == "region" {
+			region = kv.Value.AsString()
+		}
+		return true
+	})
+	if region != "eu-west-1" {
+		t.Errorf("region = %q, want eu-west-1", region)
+	}
+}
+
+type colorKey struct{}
+
+func TestWithDynamicAttributes(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithDynamicAttributes(func(ctx context.Context) []attribute.KeyValue {
+			color, _ := ctx.Value(colorKey{}).(string)
+			return []attribute.KeyValue{attribute.String("deployment.color", color)}
+		}),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+
+	for _, color := range []string{"blue", "green"} {
+		ctx := context.WithValue(context.Background(), colorKey{}, color)
+		_, span := tracer.Start(ctx, "request")
+		span.End()
+	}
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	for i, want := range []string{"blue", "green"} {
+		var got string
+		for _, kv := range spans[i].Attributes() {
+			if kv.Key == "deployment.color" {
+				got = kv.Value.AsString()
+			}
+		}
+		if got != want {
+			t.Errorf("span %d deployment.color = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestWithDefaultSpanAttributes(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithDefaultSpanAttributes(attribute.String("team", "payments")),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+	ctx := context.Background()
+
+	_, span := tracer.Start(ctx, "default")
+	span.End()
+	_, span = tracer.Start(ctx, "at start", trace.WithAttributes(attribute.String("team", "billing")))
+	span.End()
+	_, span = tracer.Start(ctx, "after start")
+	span.SetAttributes(attribute.String("team", "search"))
+	span.End()
+
+	spans := exp.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	for i, want := range []string{"payments", "billing", "search"} {
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		if got, _ := attrs.Value("team"); got.AsString() != want {
+			t.Errorf("span %q team = %q, want %q", spans[i].Name(), got.AsString(), want)
+		}
+	}
+}
+
+func TestWithBaggageToAttributes(t *testing.T) {
+	ctx := SetBaggage(context.Background(), "tenant_id", "acme")
+	ctx = SetBaggage(ctx, "region", "eu-west-1")
+	for _, tt := range []struct {
+		keys []string
+		want []attribute.Key
+	}{
+		{keys: []string{"tenant_id", "missing"}, want: []attribute.Key{"tenant_id"}},
+		{want: []attribute.Key{"tenant_id", "region"}},
+	} {
+		exp := &fakeSpanExporter{}
+		p := setupTest(t,
+			WithCustomTraceExporter(exp),
+			WithSyncSpanProcessor(),
+			WithBaggageToAttributes(tt.keys...),
+		)
+		_, span := p.TracerProvider.Tracer("test").Start(ctx, "request")
+		span.End()
+
+		attrs := exp.Spans()[0].Attributes()
+		if len(attrs) != len(tt.want) {
+			t.Errorf("keys %q: span attributes = %v, want %v", tt.keys, attrs, tt.want)
+		}
+		for _, key := range tt.want {
+			if !hasKey(attrs, key) {
+				t.Errorf("keys %q: span attributes = %v, want %s", tt.keys, attrs, key)
+			}
+		}
+	}
+}
+
+func TestWithCodeAttributes(t *testing.T) {
+	// The frames of this package are skipped, which includes this test, so
+	// only skip those of the processor itself.
+	prev := codeSkipPrefixes
+	codeSkipPrefixes = []string{"runtime.", "go.opentelemetry.io/", name + ".codeProcessor"}
+	t.Cleanup(func() { codeSkipPrefixes = prev })
+
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithCodeAttributes(),
+	)
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "work")
+	span.End()
+
+	attrs := attribute.NewSet(exp.Spans()[0].Attributes()...)
+	for key, want := range map[attribute.Key]string{
+		"code.namespace": name,
+		"code.function":  "TestWithCodeAttributes",
+	} {
+		if got, _ := attrs.Value(key); got.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, got.AsString(), want)
+		}
+	}
+	if got, _ := attrs.Value("code.filepath"); !strings.HasSuffix(got.AsString(), "processors_test.go") {
+		t.Errorf("code.filepath = %q, want processors_test.go", got.AsString())
+	}
+}
+
+func TestWithSlowSpanThreshold(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithSlowSpanThreshold(time.Second),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+	start := time.Now()
+	for name, d := range map[string]time.Duration{"fast": 10 * time.Millisecond, "slow": 2 * time.Second} {
+		_, span := tracer.Start(context.Background(), name, trace.WithTimestamp(start))
+		span.End(trace.WithTimestamp(start.Add(d)))
+	}
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	for _, s := range spans {
+		slow := s.Name() == "slow"
+		if got := hasKey(s.Attributes(), "slow"); got != slow {
+			t.Errorf("%s span has the slow attribute: %v, want %v", s.Name(), got, slow)
+		}
+		events := s.Events()
+		if got := len(events) == 1 && events[0].Name == "slow"; got != slow {
+			t.Errorf("%s span events = %v, want a slow event: %v", s.Name(), events, slow)
+		}
+	}
+}
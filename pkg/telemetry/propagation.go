@@ -0,0 +1,21 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// InjectToMap writes the trace context and baggage of ctx into carrier using
+// the global propagator, for transports such as Kafka or RabbitMQ message
+// headers.
+func InjectToMap(ctx context.Context, carrier map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(carrier))
+}
+
+// ExtractFromMap returns a copy of ctx carrying the trace context and baggage
+// read from carrier using the global propagator.
+func ExtractFromMap(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
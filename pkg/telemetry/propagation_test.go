@@ -0,0 +1,33 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMapCarrierRoundTrip(t *testing.T) {
+	p := setupGlobalTest(t)
+	ctx, span := p.TracerProvider.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+	ctx = SetBaggage(ctx, "tenant_id", "acme")
+
+	headers := map[string]string{}
+	InjectToMap(ctx, headers)
+	if headers["traceparent"] == "" {
+		t.Fatalf("headers = %v, want a traceparent", headers)
+	}
+
+	got := ExtractFromMap(context.Background(), headers)
+	sc := trace.SpanContextFromContext(got)
+	if sc.TraceID() != span.SpanContext().TraceID() || sc.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("extracted span context %v, want %v", sc, span.SpanContext())
+	}
+	if !sc.IsRemote() {
+		t.Error("extracted span context is not remote")
+	}
+	if v := GetBaggage(got, "tenant_id"); v != "acme" {
+		t.Errorf("extracted baggage tenant_id = %q, want acme", v)
+	}
+}
@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// MetricRecorder is a MeterProvider backed by a manual reader, for asserting
+// on recorded measurements in tests:
+//
+//	rec := telemetry.NewMetricRecorder()
+//	counter, _ := rec.Meter("test").Int64Counter("requests")
+//	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("route", "/")))
+//	v, ok := rec.SumValue("requests", attribute.String("route", "/")) // 2, true
+type MetricRecorder struct {
+	*sdkmetric.MeterProvider
+	reader *sdkmetric.ManualReader
+}
+
+// NewMetricRecorder returns a MetricRecorder. opts configure the meter
+// provider, for example to add views.
+func NewMetricRecorder(opts ...sdkmetric.Option) *MetricRecorder {
+	reader := sdkmetric.NewManualReader()
+	return &MetricRecorder{
+		MeterProvider: sdkmetric.NewMeterProvider(append(opts, sdkmetric.WithReader(reader))...),
+		reader:        reader,
+	}
+}
+
+// Collect returns everything recorded so far. Collection errors are reported
+// to the global error handler.
+func (r *MetricRecorder) Collect() metricdata.ResourceMetrics {
+	var rm metricdata.ResourceMetrics
+	if err := r.reader.Collect(context.Background(), &rm); err != nil {
+		otel.Handle(err)
+	}
+	return rm
+}
+
+// SumValue returns the value of the counter or up-down counter named name for
+// exactly the attributes attrs, and whether it was recorded.
+func (r *MetricRecorder) SumValue(name string, attrs ...attribute.KeyValue) (float64, bool) {
+	set := attribute.NewSet(attrs...)
+	switch d := r.find(name).(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range d.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return float64(dp.Value), true
+			}
+		}
+	case metricdata.Sum[float64]:
+		for _, dp := range d.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return dp.Value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// HistogramCount returns the number of measurements recorded by the histogram
+// named name for exactly the attributes attrs, and whether it was recorded.
+func (r *MetricRecorder) HistogramCount(name string, attrs ...attribute.KeyValue) (uint64, bool) {
+	set := attribute.NewSet(attrs...)
+	switch d := r.find(name).(type) {
+	case metricdata.Histogram[int64]:
+		for _, dp := range d.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return dp.Count, true
+			}
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range d.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return dp.Count, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// find returns the aggregation of the metric named name, or nil.
+func (r *MetricRecorder) find(name string) metricdata.Aggregation {
+	rm := r.Collect()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
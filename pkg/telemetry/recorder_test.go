@@ -0,0 +1,52 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestMetricRecorderCounter(t *testing.T) {
+	rec := NewMetricRecorder()
+	defer rec.Shutdown(context.Background())
+	counter, err := rec.Meter("test").Int64Counter("requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := attribute.String("route", "/")
+	counter.Add(context.Background(), 2, metric.WithAttributes(root))
+	counter.Add(context.Background(), 3, metric.WithAttributes(root))
+	counter.Add(context.Background(), 1, metric.WithAttributes(attribute.String("route", "/users")))
+
+	if v, ok := rec.SumValue("requests", root); !ok || v != 5 {
+		t.Errorf("SumValue(requests, /) = %v, %v, want 5, true", v, ok)
+	}
+	if _, ok := rec.SumValue("requests"); ok {
+		t.Error("SumValue found requests without attributes")
+	}
+	if _, ok := rec.SumValue("missing"); ok {
+		t.Error("SumValue found a metric never recorded")
+	}
+}
+
+func TestMetricRecorderHistogramCount(t *testing.T) {
+	rec := NewMetricRecorder()
+	defer rec.Shutdown(context.Background())
+	hist, err := rec.Meter("test").Float64Histogram("latency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := attribute.String("method", "GET")
+	for _, v := range []float64{0.1, 0.4, 2} {
+		hist.Record(context.Background(), v, metric.WithAttributes(get))
+	}
+
+	if n, ok := rec.HistogramCount("latency", get); !ok || n != 3 {
+		t.Errorf("HistogramCount(latency, GET) = %d, %v, want 3, true", n, ok)
+	}
+	if _, ok := rec.HistogramCount("latency", attribute.String("method", "POST")); ok {
+		t.Error("HistogramCount found POST measurements")
+	}
+}
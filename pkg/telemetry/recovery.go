@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recoveryConfig struct {
+	repanic bool
+}
+
+// RecoveryOption configures RecoveryHandler.
+type RecoveryOption func(*recoveryConfig)
+
+// WithRepanic makes RecoveryHandler panic again with the original value after
+// recording it instead of responding, so that an outer handler or the server
+// can deal with it.
+func WithRepanic() RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.repanic = true
+	}
+}
+
+// RecoveryHandler returns middleware that recovers panics in next, records
+// them on the span of the request with their stack trace, sets the span
+// status to Error and responds with 500 Internal Server Error.
+// It must be wrapped by the HTTP instrumentation, for example otelhttp, so
+// that the request context carries the server span:
+//
+//	handler := otelhttp.NewHandler(telemetry.RecoveryHandler(mux), "/")
+//
+// http.ErrAbortHandler is always re-panicked, as net/http expects.
+func RecoveryHandler(next http.Handler, opts ...RecoveryOption) http.Handler {
+	var cfg recoveryConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return middleware{next: next, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			recordPanic(trace.SpanFromContext(r.Context()), v, debug.Stack())
+			if cfg.repanic {
+				panic(v)
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})}
+}
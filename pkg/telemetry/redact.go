@@ -0,0 +1,202 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// keyMatcher matches attribute keys exactly, or by prefix for patterns ending
+// in "*".
+type keyMatcher struct {
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+func newKeyMatcher(patterns []string) keyMatcher {
+	m := keyMatcher{exact: make(map[string]struct{})}
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			m.prefixes = append(m.prefixes, prefix)
+		} else {
+			m.exact[p] = struct{}{}
+		}
+	}
+	return m
+}
+
+func (m keyMatcher) match(key string) bool {
+	if _, ok := m.exact[key]; ok {
+		return true
+	}
+	for _, p := range m.prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// spanTransformer is implemented by span processors that rewrite spans before
+// they are exported. The SDK does not allow changing a span once it has ended,
+// so newTracerProvider applies them to the spans handed to each exporter.
+type spanTransformer interface {
+	transform(sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan
+}
+
+// transformExporter applies transformers to spans before exporting them.
+type transformExporter struct {
+	sdktrace.SpanExporter
+	transformers []spanTransformer
+}
+
+func (e transformExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	out := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, s := range spans {
+		for _, t := range e.transformers {
+			s = t.transform(s)
+		}
+		out[i] = s
+	}
+	return e.SpanExporter.ExportSpans(ctx, out)
+}
+
+// redactedSpan is a span with replaced sets of attributes and events.
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+}
+
+func (s redactedSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+func (s redactedSpan) Events() []sdktrace.Event {
+	return s.events
+}
+
+// redact removes the attributes matching keys from s and its events. It
+// returns s itself when nothing matches.
+func redact(keys keyMatcher, s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, redacted := redactAttributes(keys, s.Attributes())
+	events := s.Events()
+	var redactedEvents []sdktrace.Event
+	for i, ev := range events {
+		kept, ok := redactAttributes(keys, ev.Attributes)
+		if !ok {
+			continue
+		}
+		if redactedEvents == nil {
+			redactedEvents = append([]sdktrace.Event(nil), events...)
+		}
+		redactedEvents[i].Attributes = kept
+	}
+	if !redacted && redactedEvents == nil {
+		return s
+	}
+	if redactedEvents == nil {
+		redactedEvents = events
+	}
+	return redactedSpan{ReadOnlySpan: s, attrs: attrs, events: redactedEvents}
+}
+
+// redactAttributes returns attrs without the ones matching keys, and whether
+// any matched.
+func redactAttributes(keys keyMatcher, attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	kept := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if !keys.match(string(kv.Key)) {
+			kept = append(kept, kv)
+		}
+	}
+	if len(kept) == len(attrs) {
+		return attrs, false
+	}
+	return kept, true
+}
+
+// redactingExporter removes matching attributes from spans before exporting
+// them with the wrapped exporter.
+type redactingExporter struct {
+	sdktrace.SpanExporter
+	keys keyMatcher
+}
+
+// NewRedactingExporter returns a SpanExporter that removes the attributes
+// named by keys from every span and span event before exporting it with next.
+// A key ending in "*" matches every attribute with that prefix, for example
+// "user.*". Use it to redact spans of a pipeline not built by Setup.
+func NewRedactingExporter(next sdktrace.SpanExporter, keys []string) sdktrace.SpanExporter {
+	return redactingExporter{SpanExporter: next, keys: newKeyMatcher(keys)}
+}
+
+func (e redactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	out := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, s := range spans {
+		out[i] = redact(e.keys, s)
+	}
+	return e.SpanExporter.ExportSpans(ctx, out)
+}
+
+// redactingProcessor removes matching attributes from spans before export.
+type redactingProcessor struct {
+	keys keyMatcher
+}
+
+// NewRedactingProcessor returns a SpanProcessor that removes the attributes
+// named by keys from every span and span event before it is exported. Keys
+// are matched like in NewRedactingExporter.
+// The SDK does not allow changing ended spans, so the processor only takes
+// effect when registered with WithSpanProcessor, which redacts the spans
+// handed to every exporter of the pipeline. Use NewRedactingExporter with a
+// TracerProvider built without Setup.
+func NewRedactingProcessor(keys []string) sdktrace.SpanProcessor {
+	return redactingProcessor{keys: newKeyMatcher(keys)}
+}
+
+func (p redactingProcessor) transform(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	return redact(p.keys, s)
+}
+
+func (p redactingProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (p redactingProcessor) OnEnd(sdktrace.ReadOnlySpan)                     {}
+func (p redactingProcessor) Shutdown(context.Context) error                  { return nil }
+func (p redactingProcessor) ForceFlush(context.Context) error                { return nil }
+
+// redactingLogProcessor removes matching attributes from log records.
+type redactingLogProcessor struct {
+	keys keyMatcher
+}
+
+// NewRedactingLogProcessor returns a log Processor that removes the attributes
+// named by keys from every log record. Keys are matched like in
+// NewRedactingExporter. Register it with WithLogProcessor so it runs before
+// records are exported.
+func NewRedactingLogProcessor(keys []string) sdklog.Processor {
+	return redactingLogProcessor{keys: newKeyMatcher(keys)}
+}
+
+func (p redactingLogProcessor) OnEmit(_ context.Context, r *sdklog.Record) error {
+	var kept []log.KeyValue
+	redacted := false
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		if p.keys.match(kv.Key) {
+			redacted = true
+		} else {
+			kept = append(kept, kv)
+		}
+		return true
+	})
+	if redacted {
+		r.SetAttributes(kept...)
+	}
+	return nil
+}
+
+func (p redactingLogProcessor) Shutdown(context.Context) error   { return nil }
+func (p redactingLogProcessor) ForceFlush(context.Context) error { return nil }
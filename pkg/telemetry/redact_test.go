@@ -0,0 +1,109 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var redactKeys = []string{"password", "user.*"}
+
+func hasKey(attrs []attribute.KeyValue, key attribute.Key) bool {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func startRedactedSpan(tracer trace.Tracer) {
+	_, span := tracer.Start(context.Background(), "login", trace.WithAttributes(
+		attribute.String("password", "hunter2"),
+		attribute.String("user.email", "a@example.com"),
+		attribute.String("http.method", "POST"),
+	))
+	span.AddEvent("retry", trace.WithAttributes(
+		attribute.String("user.id", "42"),
+		attribute.Int("attempt", 2),
+	))
+	span.End()
+}
+
+func checkRedacted(t *testing.T, spans []sdktrace.ReadOnlySpan) {
+	t.Helper()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	s := spans[0]
+	for _, key := range []attribute.Key{"password", "user.email"} {
+		if hasKey(s.Attributes(), key) {
+			t.Errorf("span attribute %q was not redacted", key)
+		}
+	}
+	if !hasKey(s.Attributes(), "http.method") {
+		t.Error("span attribute http.method was removed")
+	}
+	events := s.Events()
+	if len(events) != 1 {
+		t.Fatalf("span has %d events, want 1", len(events))
+	}
+	if hasKey(events[0].Attributes, "user.id") {
+		t.Error("event attribute user.id was not redacted")
+	}
+	if !hasKey(events[0].Attributes, "attempt") {
+		t.Error("event attribute attempt was removed")
+	}
+}
+
+func TestRedactingExporter(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewRedactingExporter(exp, redactKeys)))
+	defer tp.Shutdown(context.Background())
+
+	startRedactedSpan(tp.Tracer("test"))
+	checkRedacted(t, exp.Spans())
+}
+
+func TestRedactingProcessor(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithSpanProcessor(NewRedactingProcessor(redactKeys)),
+	)
+
+	startRedactedSpan(p.TracerProvider.Tracer("test"))
+	checkRedacted(t, exp.Spans())
+}
+
+func TestRedactingLogProcessor(t *testing.T) {
+	exp := &memLogExporter{}
+	p := setupTest(t,
+		WithCustomLogExporter(exp),
+		WithSyncSpanProcessor(),
+		WithLogProcessor(NewRedactingLogProcessor(redactKeys)),
+	)
+
+	var r log.Record
+	r.SetBody(log.StringValue("login"))
+	r.AddAttributes(log.String("user.email", "a@example.com"), log.String("outcome", "ok"))
+	p.LoggerProvider.Logger("test").Emit(context.Background(), r)
+
+	records := exp.Records()
+	if len(records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(records))
+	}
+	var keys []string
+	records[0].WalkAttributes(func(kv log.KeyValue) bool {
+		keys = append(keys, kv.Key)
+		return true
+	})
+	if len(keys) != 1 || keys[0] != "outcome" {
+		t.Errorf("record attributes = %v, want [outcome]", keys)
+	}
+}
@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// currentProviders are the providers registered as globals by the last Setup.
+var currentProviders atomic.Pointer[Providers]
+
+// Reset shuts down the providers registered as globals by the last Setup and
+// restores the noop providers and propagator, so that a following Setup
+// starts from a clean state, for example between tests or on a plugin reload.
+// Calling Reset again, or when no pipeline is registered, does nothing.
+func Reset(ctx context.Context) error {
+	p := currentProviders.Swap(nil)
+	if p == nil {
+		return nil
+	}
+
+	otel.SetTracerProvider(tracenoop.NewTracerProvider())
+	otel.SetMeterProvider(metricnoop.NewMeterProvider())
+	global.SetLoggerProvider(lognoop.NewLoggerProvider())
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	serviceName.Store("")
+	currentHealth.Store(nil)
+	currentSampling.Store(nil)
+	resetInstruments()
+
+	return p.Shutdown(ctx)
+}
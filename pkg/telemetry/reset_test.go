@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestResetThenSetup(t *testing.T) {
+	ctx := context.Background()
+	export := func() {
+		_, span := otel.Tracer("test").Start(ctx, "work")
+		span.End()
+	}
+
+	first := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(first), WithSyncSpanProcessor())
+	export()
+	if err := Reset(ctx); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if err := Reset(ctx); err != nil {
+		t.Fatalf("second Reset: %v", err)
+	}
+	if _, span := otel.Tracer("test").Start(ctx, "after reset"); span.IsRecording() {
+		t.Error("the global tracer provider still records after Reset")
+	}
+
+	second := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(second), WithSyncSpanProcessor())
+	export()
+	export()
+
+	if got := len(first.Spans()); got != 1 {
+		t.Errorf("first pipeline exported %d spans, want 1", got)
+	}
+	if got := len(second.Spans()); got != 2 {
+		t.Errorf("second pipeline exported %d spans, want 2", got)
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	if !first.shutdown {
+		t.Error("Reset did not shut down the first pipeline")
+	}
+}
@@ -0,0 +1,301 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"gopkg.in/yaml.v3"
+)
+
+// BuildCommit and BuildTime describe the build when set with the linker, for
+// example:
+//
+//	go build -ldflags "-X github.com/billmeyer/go-otel-core/pkg/telemetry.BuildCommit=$(git rev-parse HEAD)"
+//
+// NewResource records them as the vcs.revision and build.time attributes.
+var (
+	BuildCommit string
+	BuildTime   string
+)
+
+// instanceID is the default service.instance.id, generated once so every
+// resource of the process shares it.
+var instanceID = sync.OnceValue(uuid.NewString)
+
+// readBuildInfo is a variable so that the build info can be faked.
+var readBuildInfo = debug.ReadBuildInfo
+
+type resourceConfig struct {
+	attrs             []attribute.KeyValue
+	detectorsOverride bool
+}
+
+// ResourceOption configures the resource created by NewResource.
+type ResourceOption func(*resourceConfig)
+
+// WithServiceName sets service.name.
+func WithServiceName(name string) ResourceOption {
+	return WithResourceAttributes(semconv.ServiceName(name))
+}
+
+// WithServiceVersion sets service.version.
+func WithServiceVersion(version string) ResourceOption {
+	return WithResourceAttributes(semconv.ServiceVersion(version))
+}
+
+// WithInstanceID sets service.instance.id, for example to the pod name from
+// HOSTNAME, replacing the random UUID NewResource generates per process.
+func WithInstanceID(id string) ResourceOption {
+	return WithResourceAttributes(semconv.ServiceInstanceID(id))
+}
+
+// WithDeploymentEnvironment sets deployment.environment.
+func WithDeploymentEnvironment(env string) ResourceOption {
+	return WithResourceAttributes(semconv.DeploymentEnvironment(env))
+}
+
+// WithResourceAttributes adds attrs to the resource.
+func WithResourceAttributes(attrs ...attribute.KeyValue) ResourceOption {
+	return func(c *resourceConfig) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithDetectorsOverride gives detected attributes precedence over the
+// attributes set by the other options when both define the same key.
+func WithDetectorsOverride() ResourceOption {
+	return func(c *resourceConfig) {
+		c.detectorsOverride = true
+	}
+}
+
+// WithBuildInfo sets service.version to the version of the main module and
+// vcs.revision to the commit it was built from, as recorded by the Go
+// toolchain. Values that are not available, such as the version of a
+// development build, are left unset.
+func WithBuildInfo() ResourceOption {
+	return func(c *resourceConfig) {
+		bi, ok := readBuildInfo()
+		if !ok {
+			return
+		}
+		if v := bi.Main.Version; v != "" && v != "(devel)" {
+			c.attrs = append(c.attrs, semconv.ServiceVersion(v))
+		}
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" && s.Value != "" {
+				c.attrs = append(c.attrs, attribute.String("vcs.revision", s.Value))
+			}
+		}
+	}
+}
+
+// NewResource returns a resource describing the service with the attributes
+// set by opts, where later options take precedence, the build attributes of
+// BuildCommit and BuildTime, a service.instance.id unique to the process,
+// and the detected telemetry SDK, process, OS and host attributes. When an
+// attribute set by opts is also detected, such as host.name, the value from
+// opts wins unless WithDetectorsOverride is used.
+// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME override both; a malformed
+// OTEL_RESOURCE_ATTRIBUTES, see ParseResourceAttributes, is an error.
+//
+// The resource uses the schema URL of the SDK's detectors, which may be newer
+// than the semantic conventions this package is built with. Merging it with a
+// resource using a different schema URL fails with
+// resource.ErrSchemaURLConflict.
+func NewResource(ctx context.Context, opts ...ResourceOption) (*resource.Resource, error) {
+	cfg := resourceConfig{attrs: []attribute.KeyValue{semconv.ServiceInstanceID(instanceID())}}
+	if BuildCommit != "" {
+		cfg.attrs = append(cfg.attrs, attribute.String("vcs.revision", BuildCommit))
+	}
+	if BuildTime != "" {
+		cfg.attrs = append(cfg.attrs, attribute.String("build.time", BuildTime))
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	detected, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithProcess(),
+		resource.WithOS(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	user := resource.NewSchemaless(cfg.attrs...)
+	envAttrs, err := ParseResourceAttributes(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("%w in OTEL_RESOURCE_ATTRIBUTES", err)
+	}
+	if name := strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")); name != "" {
+		envAttrs = append(envAttrs, semconv.ServiceName(name))
+	}
+	env := resource.NewSchemaless(envAttrs...)
+
+	// Merge gives precedence to its second argument.
+	base, override := detected, user
+	if cfg.detectorsOverride {
+		base, override = user, detected
+	}
+	res, err := resource.Merge(base, override)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Merge(res, env)
+}
+
+// ParseResourceAttributes parses s in the OTEL_RESOURCE_ATTRIBUTES format, a
+// comma-separated list of key=value pairs whose values are percent-encoded,
+// into string attributes:
+//
+//	service.namespace=shop,team=payments%2Cbilling
+//
+// Whitespace around keys and values and empty entries are ignored. An entry
+// without "=", with an empty key or with an invalid percent-encoding is an
+// error naming the entry; no attributes are returned then.
+func ParseResourceAttributes(s string) ([]attribute.KeyValue, error) {
+	var attrs []attribute.KeyValue
+	for i, entry := range strings.Split(s, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("telemetry: resource attribute %d: want key=value, got %q", i+1, entry)
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("telemetry: resource attribute %q: %w", key, err)
+		}
+		attrs = append(attrs, attribute.String(key, decoded))
+	}
+	return attrs, nil
+}
+
+// ResourceFromFile returns the detected resource, like SetupFromEnv uses,
+// merged with the attributes in the JSON or YAML file at path. The file holds
+// a flat map of attribute keys to strings, booleans, numbers or lists of one
+// of those, and its attributes take precedence over detected ones:
+//
+//	team: payments
+//	cloud.region: eu-west-1
+//	tier: 2
+//
+// A missing file is not an error; the detected resource is returned as is.
+func ResourceFromFile(path string) (*resource.Resource, error) {
+	res, err := detectResource(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: reading resource file: %w", err)
+	}
+
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("telemetry: parsing resource file %s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		kv, ok := attributeFromValue(k, values[k])
+		if !ok {
+			return nil, fmt.Errorf("telemetry: resource file %s: attribute %q has unsupported value %v", path, k, values[k])
+		}
+		attrs = append(attrs, kv)
+	}
+	return resource.Merge(res, resource.NewSchemaless(attrs...))
+}
+
+// detectResource returns the resource described by the environment and the
+// host, merged with resource.Default.
+func detectResource(ctx context.Context) (*resource.Resource, error) {
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithProcess(),
+		resource.WithOS(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Merge(resource.Default(), res)
+}
+
+// attributeFromValue converts a decoded JSON or YAML value to an attribute.
+// Lists must hold values of a single type.
+func attributeFromValue(key string, v any) (attribute.KeyValue, bool) {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(key, v), true
+	case bool:
+		return attribute.Bool(key, v), true
+	case int:
+		return attribute.Int(key, v), true
+	case float64:
+		return attribute.Float64(key, v), true
+	case []any:
+		return sliceAttribute(key, v)
+	}
+	return attribute.KeyValue{}, false
+}
+
+func sliceAttribute(key string, values []any) (attribute.KeyValue, bool) {
+	if len(values) == 0 {
+		return attribute.StringSlice(key, nil), true
+	}
+	switch values[0].(type) {
+	case string:
+		s, ok := convertSlice[string](values)
+		return attribute.StringSlice(key, s), ok
+	case bool:
+		s, ok := convertSlice[bool](values)
+		return attribute.BoolSlice(key, s), ok
+	case int:
+		s, ok := convertSlice[int](values)
+		return attribute.IntSlice(key, s), ok
+	case float64:
+		s, ok := convertSlice[float64](values)
+		return attribute.Float64Slice(key, s), ok
+	}
+	return attribute.KeyValue{}, false
+}
+
+func convertSlice[T any](values []any) ([]T, bool) {
+	out := make([]T, len(values))
+	for i, v := range values {
+		t, ok := v.(T)
+		if !ok {
+			return nil, false
+		}
+		out[i] = t
+	}
+	return out, true
+}
@@ -0,0 +1,244 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// resourceValue returns the value of key in res as a string, and whether it
+// is set.
+func resourceValue(res *resource.Resource, key attribute.Key) (string, bool) {
+	v, ok := res.Set().Value(key)
+	return v.Emit(), ok
+}
+
+func TestResourceFromFile(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("OTEL_SERVICE_NAME", "checkout")
+	files := map[string]string{
+		"resource.yaml": "team: payments\ntier: 2\nsampled: true\nzones: [a, b]\nservice.name: from-file\n",
+		"resource.json": `{"team": "payments", "tier": 2, "sampled": true, "zones": ["a", "b"], "service.name": "from-file"}`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			res, err := ResourceFromFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[attribute.Key]string{
+				"team":    "payments",
+				"tier":    "2",
+				"sampled": "true",
+				"zones":   `["a","b"]`,
+				// The file takes precedence over the detected service name.
+				semconv.ServiceNameKey: "from-file",
+			}
+			for key, value := range want {
+				if got, _ := resourceValue(res, key); got != value {
+					t.Errorf("%s = %s, want %s", key, got, value)
+				}
+			}
+			if _, ok := resourceValue(res, semconv.TelemetrySDKNameKey); !ok {
+				t.Error("detected attributes are missing")
+			}
+		})
+	}
+}
+
+func TestResourceFromFileMissing(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "checkout")
+	res, err := ResourceFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resourceValue(res, semconv.ServiceNameKey); got != "checkout" {
+		t.Errorf("service.name = %s, want the detected checkout", got)
+	}
+}
+
+func TestResourceFromFileUnsupportedValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resource.yaml")
+	if err := os.WriteFile(path, []byte("owner:\n  name: payments\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResourceFromFile(path); err == nil {
+		t.Error("a nested map was accepted")
+	}
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	prev := readBuildInfo
+	t.Cleanup(func() { readBuildInfo = prev })
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main:     debug.Module{Path: "example.com/checkout", Version: "v1.4.2"},
+			Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}},
+		}, true
+	}
+	res, err := NewResource(context.Background(), WithBuildInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[attribute.Key]string{
+		semconv.ServiceVersionKey: "v1.4.2",
+		"vcs.revision":            "abc123",
+	} {
+		if got, _ := resourceValue(res, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return nil, false }
+	res, err = NewResource(context.Background(), WithBuildInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := resourceValue(res, semconv.ServiceVersionKey); ok {
+		t.Errorf("service.version = %q without build info, want it unset", v)
+	}
+}
+
+func TestNewResourceMergePrecedence(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no host name to detect: %v", err)
+	}
+	custom := WithResourceAttributes(semconv.HostName("custom-host"))
+
+	res, err := NewResource(context.Background(), custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resourceValue(res, semconv.HostNameKey); got != "custom-host" {
+		t.Errorf("host.name = %q, want the configured custom-host", got)
+	}
+
+	res, err = NewResource(context.Background(), custom, WithDetectorsOverride())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resourceValue(res, semconv.HostNameKey); got != host {
+		t.Errorf("host.name with WithDetectorsOverride = %q, want the detected %q", got, host)
+	}
+
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "host.name=env-host")
+	res, err = NewResource(context.Background(), custom, WithDetectorsOverride())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resourceValue(res, semconv.HostNameKey); got != "env-host" {
+		t.Errorf("host.name with OTEL_RESOURCE_ATTRIBUTES = %q, want env-host", got)
+	}
+}
+
+func TestBuildCommitAndTime(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	prevCommit, prevTime := BuildCommit, BuildTime
+	t.Cleanup(func() { BuildCommit, BuildTime = prevCommit, prevTime })
+	BuildCommit, BuildTime = "0a1b2c3", "2026-10-15T08:00:00Z"
+
+	res, err := NewResource(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[attribute.Key]string{
+		"vcs.revision": "0a1b2c3",
+		"build.time":   "2026-10-15T08:00:00Z",
+	} {
+		if got, _ := resourceValue(res, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestParseResourceAttributes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []attribute.KeyValue
+		wantErr string
+	}{
+		{in: "", want: nil},
+		{
+			in: "service.namespace=shop, team=payments%2Cbilling,note=a%20b=c",
+			want: []attribute.KeyValue{
+				attribute.String("service.namespace", "shop"),
+				attribute.String("team", "payments,billing"),
+				attribute.String("note", "a b=c"),
+			},
+		},
+		{in: ",tier=1,, ,", want: []attribute.KeyValue{attribute.String("tier", "1")}},
+		{in: "tier=", want: []attribute.KeyValue{attribute.String("tier", "")}},
+		{in: "team=payments,tier", wantErr: `resource attribute 2: want key=value, got "tier"`},
+		{in: " =payments", wantErr: `resource attribute 1: want key=value, got " =payments"`},
+		{in: "team=100%", wantErr: `resource attribute "team": invalid URL escape "%"`},
+	}
+	for _, tt := range tests {
+		got, err := ParseResourceAttributes(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseResourceAttributes(%q) error = %v, want %s", tt.in, err, tt.wantErr)
+			}
+			if got != nil {
+				t.Errorf("ParseResourceAttributes(%q) = %v with an error, want nil", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseResourceAttributes(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=payments,tier")
+	if _, err := NewResource(context.Background()); err == nil || !strings.Contains(err.Error(), "in OTEL_RESOURCE_ATTRIBUTES") {
+		t.Errorf("NewResource error = %v, want the malformed OTEL_RESOURCE_ATTRIBUTES", err)
+	}
+}
+
+func TestServiceInstanceID(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+
+	res, err := NewResource(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, ok := resourceValue(res, semconv.ServiceInstanceIDKey)
+	if _, err := uuid.Parse(id); !ok || err != nil {
+		t.Errorf("service.instance.id = %q, want a UUID", id)
+	}
+	res, err = NewResource(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := resourceValue(res, semconv.ServiceInstanceIDKey); again != id {
+		t.Errorf("service.instance.id = %q, then %q, want it stable for the process", id, again)
+	}
+
+	res, err = NewResource(context.Background(), WithInstanceID("checkout-7d9f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resourceValue(res, semconv.ServiceInstanceIDKey); got != "checkout-7d9f" {
+		t.Errorf("service.instance.id with WithInstanceID = %q, want checkout-7d9f", got)
+	}
+}
@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// samplerStats counts the decisions of the sampler of a pipeline.
+type samplerStats struct {
+	// sampler is set before the stats are published in currentSampling. Its
+	// description is read on every request, as samplers such as
+	// DynamicSampler change it at run time.
+	sampler                  sdktrace.Sampler
+	sampled, recorded, drops atomic.Int64
+}
+
+// currentSampling is the sampler stats of the pipeline registered by the last
+// Setup.
+var currentSampling atomic.Pointer[samplerStats]
+
+// statsSampler counts the decisions of Sampler in stats.
+type statsSampler struct {
+	sdktrace.Sampler
+	stats *samplerStats
+}
+
+func newStatsSampler(sampler sdktrace.Sampler, stats *samplerStats) statsSampler {
+	stats.sampler = sampler
+	return statsSampler{Sampler: sampler, stats: stats}
+}
+
+func (s statsSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.Sampler.ShouldSample(p)
+	switch res.Decision {
+	case sdktrace.RecordAndSample:
+		s.stats.sampled.Add(1)
+	case sdktrace.RecordOnly:
+		s.stats.recorded.Add(1)
+	default:
+		s.stats.drops.Add(1)
+	}
+	return res
+}
+
+// SamplerDebugHandler returns an http.Handler reporting the sampler of the
+// global pipeline and how many sampling decisions it made of each kind since
+// Setup, as JSON. It responds with 503 when no pipeline is registered.
+func SamplerDebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		stats := currentSampling.Load()
+		if stats == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "not configured"})
+			return
+		}
+
+		var desc string
+		if stats.sampler != nil {
+			desc = stats.sampler.Description()
+		}
+		body := struct {
+			Sampler   string           `json:"sampler"`
+			Decisions map[string]int64 `json:"decisions"`
+		}{
+			Sampler: desc,
+			Decisions: map[string]int64{
+				"record_and_sample": stats.sampled.Load(),
+				"record_only":       stats.recorded.Load(),
+				"drop":              stats.drops.Load(),
+			},
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func samplerDescription(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	SamplerDebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/sampler", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var body struct {
+		Sampler string `json:"sampler"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Sampler
+}
+
+func TestSamplerDebugHandlerFollowsSampler(t *testing.T) {
+	sampler := NewDynamicSampler(0.25)
+	setupGlobalTest(t, WithSampler(sampler))
+
+	if got, want := samplerDescription(t), "DynamicSampler{0.25}"; got != want {
+		t.Errorf("sampler = %q, want %q", got, want)
+	}
+	sampler.SetRatio(1)
+	if got, want := samplerDescription(t), "DynamicSampler{1}"; got != want {
+		t.Errorf("sampler after SetRatio = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DynamicSampler is a trace ID ratio based sampler whose ratio can be changed
+// while the application is running, for example from an admin endpoint.
+// It is safe for concurrent use.
+type DynamicSampler struct {
+	state atomic.Pointer[dynamicSamplerState]
+}
+
+// dynamicSamplerState holds a ratio and its sampler, which are swapped
+// together. TraceIDRatioBased returns samplers of different types depending
+// on the ratio, so they cannot be stored in an atomic.Value directly.
+type dynamicSamplerState struct {
+	ratio   float64
+	sampler sdktrace.Sampler
+}
+
+var _ sdktrace.Sampler = (*DynamicSampler)(nil)
+
+// NewDynamicSampler returns a DynamicSampler sampling initialRatio of all
+// traces. Pass it to WithSampler, optionally wrapped in sdktrace.ParentBased.
+func NewDynamicSampler(initialRatio float64) *DynamicSampler {
+	s := &DynamicSampler{}
+	s.SetRatio(initialRatio)
+	return s
+}
+
+// SetRatio changes the fraction of traces sampled by subsequent decisions.
+// Ratios >= 1 sample every trace and ratios <= 0 sample none.
+func (s *DynamicSampler) SetRatio(ratio float64) {
+	s.state.Store(&dynamicSamplerState{ratio: ratio, sampler: sdktrace.TraceIDRatioBased(ratio)})
+}
+
+// Ratio returns the current sampling ratio.
+func (s *DynamicSampler) Ratio() float64 {
+	return s.state.Load().ratio
+}
+
+func (s *DynamicSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.state.Load().sampler.ShouldSample(p)
+}
+
+func (s *DynamicSampler) Description() string {
+	return fmt.Sprintf("DynamicSampler{%g}", s.Ratio())
+}
+
+// SamplingRule assigns a sampling ratio to the spans it matches.
+type SamplingRule struct {
+	// SpanName matches spans with exactly this name. Empty matches any name.
+	SpanName string
+	// Attributes match spans started with all of these attributes. Only
+	// attributes passed when the span is started are visible to samplers.
+	Attributes []attribute.KeyValue
+	// Ratio is the fraction of matching traces to sample.
+	Ratio float64
+}
+
+func (r SamplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if r.SpanName != "" && r.SpanName != p.Name {
+		return false
+	}
+	for _, want := range r.Attributes {
+		found := false
+		for _, kv := range p.Attributes {
+			if kv.Key == want.Key && kv.Value == want.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleSampler samples spans with the ratio of the first matching rule.
+type ruleSampler struct {
+	rules    []SamplingRule
+	samplers []sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+// NewRuleSampler returns a Sampler that samples each span with the ratio of
+// the first rule matching it, for example to keep every /checkout trace but
+// only 1% of /health ones. Spans matching no rule are sampled like the SDK
+// default, ParentBased(AlwaysSample); add a final rule without SpanName and
+// Attributes to change that. Pass it to WithSampler.
+func NewRuleSampler(rules []SamplingRule) sdktrace.Sampler {
+	s := &ruleSampler{
+		rules:    rules,
+		samplers: make([]sdktrace.Sampler, len(rules)),
+		fallback: sdktrace.ParentBased(sdktrace.AlwaysSample()),
+	}
+	for i, r := range rules {
+		s.samplers[i] = sdktrace.TraceIDRatioBased(r.Ratio)
+	}
+	return s
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for i, r := range s.rules {
+		if r.matches(p) {
+			return s.samplers[i].ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{rules:%d}", len(s.rules))
+}
+
+// spanKindSampler delegates to the sampler of the kind of each span.
+type spanKindSampler struct {
+	samplers map[trace.SpanKind]sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+// NewSpanKindSampler returns a Sampler that samples each span with the sampler
+// of its kind, for example sdktrace.AlwaysSample for trace.SpanKindServer and
+// sdktrace.NeverSample for trace.SpanKindInternal. Spans of other kinds are
+// sampled with fallback, or like the SDK default, ParentBased(AlwaysSample),
+// when fallback is nil. Pass it to WithSampler.
+func NewSpanKindSampler(samplers map[trace.SpanKind]sdktrace.Sampler, fallback sdktrace.Sampler) sdktrace.Sampler {
+	if fallback == nil {
+		fallback = sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+	return &spanKindSampler{samplers: samplers, fallback: fallback}
+}
+
+func (s *spanKindSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if sampler, ok := s.samplers[p.Kind]; ok {
+		return sampler.ShouldSample(p)
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *spanKindSampler) Description() string {
+	kinds := make([]trace.SpanKind, 0, len(s.samplers))
+	for kind := range s.samplers {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	parts := make([]string, 0, len(kinds)+1)
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s:%s", kind, s.samplers[kind].Description()))
+	}
+	parts = append(parts, "default:"+s.fallback.Description())
+	return fmt.Sprintf("SpanKindSampler{%s}", strings.Join(parts, ","))
+}
+
+// countingSampler samples every nth span.
+type countingSampler struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+// NewCountingSampler returns a Sampler that samples exactly every nth span it
+// is asked about, the nth, 2nth and so on, instead of a random fraction, for
+// reproducible load tests. n <= 1 samples every span. It is safe for
+// concurrent use. Pass it to WithSampler.
+func NewCountingSampler(n int) sdktrace.Sampler {
+	return &countingSampler{n: uint64(max(n, 1))}
+}
+
+func (s *countingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.count.Add(1)%s.n == 0 {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *countingSampler) Description() string {
+	return fmt.Sprintf("CountingSampler{%d}", s.n)
+}
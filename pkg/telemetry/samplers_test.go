@@ -0,0 +1,140 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sampledCount returns how many of n root spans with random trace IDs s
+// samples.
+func sampledCount(s sdktrace.Sampler, n int) int {
+	sampled := 0
+	for range n {
+		var id trace.TraceID
+		_, _ = rand.Read(id[:])
+		res := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: id, Name: "span"})
+		if res.Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	return sampled
+}
+
+func TestDynamicSamplerSetRatio(t *testing.T) {
+	s := NewDynamicSampler(0)
+	if got := sampledCount(s, 100); got != 0 {
+		t.Errorf("ratio 0 sampled %d of 100 spans", got)
+	}
+
+	// TraceIDRatioBased returns a different sampler type for ratios >= 1.
+	s.SetRatio(1)
+	if got := sampledCount(s, 100); got != 100 {
+		t.Errorf("ratio 1 sampled %d of 100 spans", got)
+	}
+	if got := s.Ratio(); got != 1 {
+		t.Errorf("Ratio() = %g, want 1", got)
+	}
+
+	s.SetRatio(0.5)
+	if got := sampledCount(s, 1000); got < 400 || got > 600 {
+		t.Errorf("ratio 0.5 sampled %d of 1000 spans", got)
+	}
+	if got, want := s.Description(), "DynamicSampler{0.5}"; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+}
+
+func TestRuleSampler(t *testing.T) {
+	p := setupTest(t, WithSampler(NewRuleSampler([]SamplingRule{
+		{Attributes: []attribute.KeyValue{semconv.HTTPRoute("/health")}, Ratio: 0},
+		{Attributes: []attribute.KeyValue{semconv.HTTPRoute("/checkout")}, Ratio: 1},
+		{SpanName: "batch", Ratio: 0.5},
+	})))
+	tracer := p.TracerProvider.Tracer("test")
+
+	sampled := func(name, route string, n int) int {
+		count := 0
+		for range n {
+			_, span := tracer.Start(context.Background(), name, trace.WithAttributes(semconv.HTTPRoute(route)))
+			if span.SpanContext().IsSampled() {
+				count++
+			}
+			span.End()
+		}
+		return count
+	}
+	if got := sampled("GET", "/health", 100); got != 0 {
+		t.Errorf("sampled %d of 100 /health spans, want 0", got)
+	}
+	if got := sampled("POST", "/checkout", 100); got != 100 {
+		t.Errorf("sampled %d of 100 /checkout spans, want 100", got)
+	}
+	if got := sampled("batch", "", 1000); got < 400 || got > 600 {
+		t.Errorf("sampled %d of 1000 batch spans, want about 500", got)
+	}
+	// Spans matching no rule are sampled like the SDK default.
+	if got := sampled("GET", "/orders", 100); got != 100 {
+		t.Errorf("sampled %d of 100 unmatched spans, want 100", got)
+	}
+}
+
+func TestSpanKindSampler(t *testing.T) {
+	p := setupTest(t, WithSampler(NewSpanKindSampler(map[trace.SpanKind]sdktrace.Sampler{
+		trace.SpanKindServer:   sdktrace.AlwaysSample(),
+		trace.SpanKindInternal: sdktrace.NeverSample(),
+	}, sdktrace.NeverSample())))
+	tracer := p.TracerProvider.Tracer("test")
+
+	for kind, want := range map[trace.SpanKind]bool{
+		trace.SpanKindServer:   true,
+		trace.SpanKindInternal: false,
+		trace.SpanKindClient:   false, // The fallback.
+	} {
+		_, span := tracer.Start(context.Background(), "work", trace.WithSpanKind(kind))
+		if got := span.SpanContext().IsSampled(); got != want {
+			t.Errorf("%s span sampled = %v, want %v", kind, got, want)
+		}
+		span.End()
+	}
+}
+
+func TestCountingSampler(t *testing.T) {
+	s := NewCountingSampler(3)
+	params := sdktrace.SamplingParameters{ParentContext: context.Background(), Name: "span"}
+	for i := 1; i <= 9; i++ {
+		got := s.ShouldSample(params).Decision == sdktrace.RecordAndSample
+		if want := i%3 == 0; got != want {
+			t.Errorf("decision %d sampled = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestCountingSamplerConcurrent(t *testing.T) {
+	s := NewCountingSampler(4)
+	params := sdktrace.SamplingParameters{ParentContext: context.Background(), Name: "span"}
+	var sampled atomic.Int64
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if s.ShouldSample(params).Decision == sdktrace.RecordAndSample {
+					sampled.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := sampled.Load(); got != 200 {
+		t.Errorf("sampled %d of 800 decisions, want 200", got)
+	}
+}
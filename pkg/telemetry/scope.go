@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serviceName is the service.name of the resource passed to the last Setup.
+var serviceName atomic.Value
+
+// setServiceName records the service name found in res, if any.
+func setServiceName(res *resource.Resource) {
+	if v, ok := res.Set().Value(semconv.ServiceNameKey); ok && v.AsString() != "" {
+		serviceName.Store(v.AsString())
+	}
+}
+
+// scopeName returns name, or the configured service name when name is empty.
+func scopeName(name string) string {
+	if name != "" {
+		return name
+	}
+	if s, ok := serviceName.Load().(string); ok && s != "" {
+		return s
+	}
+	v, _ := resource.Default().Set().Value(semconv.ServiceNameKey)
+	return v.AsString()
+}
+
+// Tracer returns a Tracer from the global TracerProvider for the named
+// instrumentation scope. An empty name uses the configured service name.
+// Use opts such as trace.WithInstrumentationVersion and trace.WithSchemaURL to
+// describe the scope.
+func Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return otel.Tracer(scopeName(name), opts...)
+}
+
+// Meter returns a Meter from the global MeterProvider for the named
+// instrumentation scope. An empty name uses the configured service name.
+// Use opts such as metric.WithInstrumentationVersion and metric.WithSchemaURL
+// to describe the scope.
+func Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return otel.Meter(scopeName(name), opts...)
+}
+
+// Logger returns a Logger from the global LoggerProvider for the named
+// instrumentation scope. An empty name uses the configured service name.
+// Use opts such as log.WithInstrumentationVersion and log.WithSchemaURL to
+// describe the scope.
+func Logger(name string, opts ...log.LoggerOption) log.Logger {
+	return global.GetLoggerProvider().Logger(scopeName(name), opts...)
+}
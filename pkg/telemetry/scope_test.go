@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracerScope(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	ctx := context.Background()
+	_, err := Setup(ctx, NoneExporter, "", resource.NewSchemaless(semconv.ServiceName("checkout")),
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Reset(ctx) })
+
+	for _, name := range []string{"", "orders"} {
+		_, span := Tracer(name).Start(ctx, "scoped")
+		span.End()
+	}
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	if got := spans[0].InstrumentationScope().Name; got != "checkout" {
+		t.Errorf("scope of Tracer(\"\") = %q, want the service name checkout", got)
+	}
+	if got := spans[1].InstrumentationScope().Name; got != "orders" {
+		t.Errorf("scope of Tracer(\"orders\") = %q, want orders", got)
+	}
+}
+
+func TestScopeVersionAndSchemaURL(t *testing.T) {
+	spans := &fakeSpanExporter{}
+	logs := &memLogExporter{}
+	setupGlobalTest(t,
+		WithCustomTraceExporter(spans),
+		WithCustomLogExporter(logs),
+		WithSyncSpanProcessor(),
+	)
+	ctx := context.Background()
+
+	_, span := Tracer("orders", trace.WithInstrumentationVersion("1.2.0"), trace.WithSchemaURL(semconv.SchemaURL)).Start(ctx, "scoped")
+	span.End()
+	var r log.Record
+	r.SetBody(log.StringValue("scoped"))
+	Logger("orders", log.WithInstrumentationVersion("1.2.0"), log.WithSchemaURL(semconv.SchemaURL)).Emit(ctx, r)
+
+	if got := spans.Spans(); len(got) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(got))
+	} else if scope := got[0].InstrumentationScope(); scope.Version != "1.2.0" || scope.SchemaURL != semconv.SchemaURL {
+		t.Errorf("span scope = %+v, want version 1.2.0 and schema URL %s", scope, semconv.SchemaURL)
+	}
+	if got := logs.Records(); len(got) != 1 {
+		t.Fatalf("exported %d records, want 1", len(got))
+	} else if scope := got[0].InstrumentationScope(); scope.Version != "1.2.0" || scope.SchemaURL != semconv.SchemaURL {
+		t.Errorf("record scope = %+v, want version 1.2.0 and schema URL %s", scope, semconv.SchemaURL)
+	}
+}
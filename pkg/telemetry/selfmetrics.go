@@ -0,0 +1,257 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// signalKey is the attribute identifying the signal of the exporter metrics.
+const signalKey = attribute.Key("otelcore.signal")
+
+// selfCounter is a counter of the pipeline's own metrics. The exporters
+// recording them are built before the pipeline's MeterProvider, so the
+// counter is only created by bind, once that provider exists; measurements
+// made before are dropped.
+type selfCounter struct {
+	name string
+	opts []metric.Int64CounterOption
+
+	counter atomic.Pointer[metric.Int64Counter]
+}
+
+// selfCounter returns a counter that Setup binds to the pipeline's
+// MeterProvider.
+func (c *config) selfCounter(name, description, unit string) *selfCounter {
+	counter := &selfCounter{name: name, opts: []metric.Int64CounterOption{
+		metric.WithDescription(description),
+		metric.WithUnit(unit),
+	}}
+	c.selfCounters = append(c.selfCounters, counter)
+	return counter
+}
+
+func (c *selfCounter) bind(meter metric.Meter) error {
+	counter, err := meter.Int64Counter(c.name, c.opts...)
+	if err != nil {
+		return err
+	}
+	c.counter.Store(&counter)
+	return nil
+}
+
+func (c *selfCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	if counter := c.counter.Load(); counter != nil {
+		(*counter).Add(ctx, incr, opts...)
+	}
+}
+
+// bindSelfMetrics creates the pipeline's own metrics with meter, a meter of
+// the pipeline's MeterProvider.
+func bindSelfMetrics(cfg *config, meter metric.Meter) error {
+	for _, c := range cfg.selfCounters {
+		if err := c.bind(meter); err != nil {
+			return err
+		}
+	}
+	if m := cfg.pipelineMetrics; m != nil {
+		return m.register(meter)
+	}
+	return nil
+}
+
+// exportMetrics records the outcome of the exports of one signal and the
+// number of items waiting in the batch processor of the primary exporter.
+type exportMetrics struct {
+	attrs            metric.MeasurementOption
+	success, failure *selfCounter
+	queued           atomic.Int64
+	// capacity is the size of the batch processor queue.
+	capacity int64
+}
+
+// utilization returns the fraction of the queue in use, from 0 to 1.
+func (m *exportMetrics) utilization() float64 {
+	if m.capacity <= 0 {
+		return 0
+	}
+	return min(max(float64(m.queued.Load())/float64(m.capacity), 0), 1)
+}
+
+func (m *exportMetrics) record(ctx context.Context, err error) {
+	if err != nil {
+		m.failure.Add(ctx, 1, m.attrs)
+	} else {
+		m.success.Add(ctx, 1, m.attrs)
+	}
+}
+
+// pipelineMetrics holds the exporter metrics of every signal of a pipeline.
+type pipelineMetrics struct {
+	traces, metrics, logs exportMetrics
+
+	registration metric.Registration
+}
+
+// newPipelineMetrics returns the exporter metrics of the pipeline configured
+// by cfg. Their queue gauges are observed once register is called.
+func newPipelineMetrics(cfg *config) *pipelineMetrics {
+	success := cfg.selfCounter("otelcore.exporter.export.success", "The number of successful exports", "{export}")
+	failure := cfg.selfCounter("otelcore.exporter.export.failure", "The number of failed exports", "{export}")
+	m := &pipelineMetrics{}
+	m.traces.capacity = int64(cfg.spanQueueSize())
+	m.logs.capacity = int64(cfg.logQueueSize())
+	for signal, em := range map[string]*exportMetrics{"traces": &m.traces, "metrics": &m.metrics, "logs": &m.logs} {
+		em.attrs = metric.WithAttributes(signalKey.String(signal))
+		em.success, em.failure = success, failure
+	}
+	return m
+}
+
+// register observes the queue gauges with meter.
+func (m *pipelineMetrics) register(meter metric.Meter) error {
+	queueLength, err := meter.Int64ObservableGauge("otelcore.exporter.queue.length",
+		metric.WithDescription("The approximate number of items waiting to be exported"),
+		metric.WithUnit("{item}"))
+	if err != nil {
+		return err
+	}
+	utilization, err := meter.Float64ObservableGauge("otelcore.queue.utilization",
+		metric.WithDescription("The approximate fraction of the export queue in use, from 0 to 1"),
+		metric.WithUnit("1"))
+	if err != nil {
+		return err
+	}
+	m.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(queueLength, m.traces.queued.Load(), metric.WithAttributes(signalKey.String("traces")))
+		o.ObserveInt64(queueLength, m.logs.queued.Load(), metric.WithAttributes(signalKey.String("logs")))
+		o.ObserveFloat64(utilization, m.traces.utilization(), metric.WithAttributes(signalKey.String("traces")))
+		o.ObserveFloat64(utilization, m.logs.utilization(), metric.WithAttributes(signalKey.String("logs")))
+		return nil
+	}, queueLength, utilization)
+	return err
+}
+
+func (m *pipelineMetrics) shutdown(context.Context) error {
+	if m.registration == nil {
+		return nil
+	}
+	return m.registration.Unregister()
+}
+
+// enqueue counts an item handed to a batch processor queue of size capacity.
+// The processor may drop the item without telling, but only once its queue
+// is full, so the count stops at capacity: it then never exceeds the items
+// really waiting and returns to 0 once they are all exported.
+func enqueue(queued *atomic.Int64, capacity int64) {
+	for {
+		n := queued.Load()
+		if n >= capacity || queued.CompareAndSwap(n, n+1) {
+			return
+		}
+	}
+}
+
+// dequeue removes n exported items from queued, which stays non-negative.
+func dequeue(queued *atomic.Int64, n int64) {
+	for {
+		old := queued.Load()
+		if queued.CompareAndSwap(old, max(old-n, 0)) {
+			return
+		}
+	}
+}
+
+// queueSpanProcessor counts the sampled spans waiting in the batch processor
+// it wraps, which exports them through a dequeueSpanExporter sharing queued.
+// It hands every span to the batch processor.
+type queueSpanProcessor struct {
+	sdktrace.SpanProcessor
+	queued   *atomic.Int64
+	capacity int64
+}
+
+func (p queueSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		enqueue(p.queued, p.capacity)
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// dequeueSpanExporter removes the spans handed to it from queued.
+type dequeueSpanExporter struct {
+	sdktrace.SpanExporter
+	queued *atomic.Int64
+}
+
+func (e dequeueSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	dequeue(e.queued, int64(len(spans)))
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
+
+// queueLogProcessor counts the log records waiting in the batch processor it
+// wraps, which exports them through a dequeueLogExporter sharing queued. Like
+// queueSpanProcessor, it hands every record to the batch processor.
+type queueLogProcessor struct {
+	sdklog.Processor
+	queued   *atomic.Int64
+	capacity int64
+}
+
+func (p queueLogProcessor) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	enqueue(p.queued, p.capacity)
+	return p.Processor.OnEmit(ctx, r)
+}
+
+// dequeueLogExporter removes the records handed to it from queued.
+type dequeueLogExporter struct {
+	sdklog.Exporter
+	queued *atomic.Int64
+}
+
+func (e dequeueLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	dequeue(e.queued, int64(len(records)))
+	return e.Exporter.Export(ctx, records)
+}
+
+// metricsSpanExporter records every export in m.
+type metricsSpanExporter struct {
+	sdktrace.SpanExporter
+	m *exportMetrics
+}
+
+func (e metricsSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.m.record(ctx, err)
+	return err
+}
+
+// metricsMetricExporter records every export in m.
+type metricsMetricExporter struct {
+	sdkmetric.Exporter
+	m *exportMetrics
+}
+
+func (e metricsMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.m.record(ctx, err)
+	return err
+}
+
+// metricsLogExporter records every export in m.
+type metricsLogExporter struct {
+	sdklog.Exporter
+	m *exportMetrics
+}
+
+func (e metricsLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.m.record(ctx, err)
+	return err
+}
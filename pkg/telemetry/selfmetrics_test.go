@@ -0,0 +1,243 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// blockingSpanExporter blocks every export until release is closed, and
+// signals on started when an export begins.
+type blockingSpanExporter struct {
+	fakeSpanExporter
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingSpanExporter() *blockingSpanExporter {
+	return &blockingSpanExporter{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (e *blockingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	select {
+	case e.started <- struct{}{}:
+	default:
+	}
+	<-e.release
+	return e.fakeSpanExporter.ExportSpans(ctx, spans)
+}
+
+func TestQueueUtilization(t *testing.T) {
+	m := &exportMetrics{capacity: 4}
+	exp := newBlockingSpanExporter()
+	bsp := sdktrace.NewBatchSpanProcessor(dequeueSpanExporter{exp, &m.queued},
+		sdktrace.WithMaxQueueSize(4), sdktrace.WithMaxExportBatchSize(1))
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(queueSpanProcessor{bsp, &m.queued, m.capacity}))
+	tracer := tp.Tracer("test")
+
+	// The first span is taken by the blocked export and is no longer queued.
+	_, span := tracer.Start(context.Background(), "first")
+	span.End()
+	<-exp.started
+
+	// The queue overflows: the spans beyond the capacity are dropped and
+	// must not be counted.
+	for range 10 {
+		_, span := tracer.Start(context.Background(), "overflow")
+		span.End()
+	}
+	if got := m.queued.Load(); got != 4 {
+		t.Errorf("queued = %d, want 4", got)
+	}
+	if got := m.utilization(); got != 1 {
+		t.Errorf("utilization = %v, want 1", got)
+	}
+
+	close(exp.release)
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.queued.Load(); got != 0 {
+		t.Errorf("queued after flush = %d, want 0", got)
+	}
+	if got := m.utilization(); got != 0 {
+		t.Errorf("utilization after flush = %v, want 0", got)
+	}
+	if got := len(exp.Spans()); got != 5 {
+		t.Errorf("exported %d spans, want 5", got)
+	}
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportCallbackQueuedAfterOverflow(t *testing.T) {
+	exp := newBlockingSpanExporter()
+	var mu sync.Mutex
+	var last ExportStats
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSpanMaxQueueSize(2),
+		WithSpanMaxExportBatchSize(1),
+		WithExportCallback(func(s ExportStats) {
+			mu.Lock()
+			last = s
+			mu.Unlock()
+		}),
+	)
+	tracer := p.TracerProvider.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "first")
+	span.End()
+	<-exp.started
+	for range 10 {
+		_, span := tracer.Start(context.Background(), "overflow")
+		span.End()
+	}
+	close(exp.release)
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if last.Queued != 0 {
+		t.Errorf("Queued = %d after the queue drained, want 0", last.Queued)
+	}
+	if last.Succeeded != 3 {
+		t.Errorf("Succeeded = %d, want 3", last.Succeeded)
+	}
+}
+
+// countingSpanProcessor counts the spans it is handed, without exporting
+// them.
+type countingSpanProcessor struct {
+	sdktrace.SpanProcessor
+	ended atomic.Int64
+}
+
+func (p *countingSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) { p.ended.Add(1) }
+
+// countingLogProcessor counts the records it is handed, without exporting
+// them.
+type countingLogProcessor struct {
+	sdklog.Processor
+	emitted atomic.Int64
+}
+
+func (p *countingLogProcessor) OnEmit(context.Context, *sdklog.Record) error {
+	p.emitted.Add(1)
+	return nil
+}
+
+func TestQueueProcessorsDropNothing(t *testing.T) {
+	var queued atomic.Int64
+	spans := &countingSpanProcessor{SpanProcessor: sdktrace.NewSimpleSpanProcessor(&fakeSpanExporter{})}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(queueSpanProcessor{spans, &queued, 2}))
+	defer tp.Shutdown(context.Background())
+	for range 5 {
+		_, span := tp.Tracer("test").Start(context.Background(), "work")
+		span.End()
+	}
+	if got := spans.ended.Load(); got != 5 {
+		t.Errorf("batch processor received %d spans, want 5", got)
+	}
+	if got := queued.Load(); got != 2 {
+		t.Errorf("queued spans = %d, want the capacity, 2", got)
+	}
+	dequeueSpanExporter{&fakeSpanExporter{}, &queued}.ExportSpans(context.Background(), testSpans("a", "b", "c"))
+	if got := queued.Load(); got != 0 {
+		t.Errorf("queued spans after export = %d, want 0", got)
+	}
+
+	queued.Store(0)
+	logs := &countingLogProcessor{Processor: sdklog.NewSimpleProcessor(&memLogExporter{})}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(queueLogProcessor{logs, &queued, 2}))
+	defer lp.Shutdown(context.Background())
+	for range 5 {
+		emitSeverity(lp.Logger("test"), log.SeverityInfo)
+	}
+	if got := logs.emitted.Load(); got != 5 {
+		t.Errorf("batch processor received %d records, want 5", got)
+	}
+	if got := queued.Load(); got != 2 {
+		t.Errorf("queued records = %d, want the capacity, 2", got)
+	}
+}
+
+// checkExportCounts exports three spans through p, the last two failing, and
+// checks the exporter metrics collected by reader count them.
+func checkExportCounts(t *testing.T, p *Providers, exp *fakeSpanExporter, reader sdkmetric.Reader) {
+	t.Helper()
+	tracer := p.TracerProvider.Tracer("test")
+	export := func() {
+		_, span := tracer.Start(context.Background(), "work")
+		span.End()
+	}
+
+	export()
+	exp.setErr(errors.New("collector down"))
+	export()
+	export()
+
+	traces := attribute.NewSet(signalKey.String("traces"))
+	for name, want := range map[string]int64{
+		"otelcore.exporter.export.success": 1,
+		"otelcore.exporter.export.failure": 2,
+	} {
+		var got int64
+		for _, dp := range collectMetric(t, reader, name).(metricdata.Sum[int64]).DataPoints {
+			if dp.Attributes.Equals(&traces) {
+				got = dp.Value
+			}
+		}
+		if got != want {
+			t.Errorf("%s = %d, want %d", name, got, want)
+		}
+	}
+	if _, ok := collectMetric(t, reader, "otelcore.queue.utilization").(metricdata.Gauge[float64]); !ok {
+		t.Error("otelcore.queue.utilization is not a float64 gauge")
+	}
+}
+
+func TestExporterMetricsCountFailures(t *testing.T) {
+	recordErrors(t)
+	exp := &fakeSpanExporter{}
+	reader := sdkmetric.NewManualReader()
+	p := setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithCustomMetricReader(reader),
+		WithSyncSpanProcessor(),
+		WithExporterMetrics(),
+	)
+	checkExportCounts(t, p, exp, reader)
+}
+
+func TestExporterMetricsAfterReset(t *testing.T) {
+	recordErrors(t)
+	setupGlobalTest(t)
+	if err := Reset(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reset left the noop meter provider global; the metrics must still
+	// reach the new pipeline.
+	exp := &fakeSpanExporter{}
+	reader := sdkmetric.NewManualReader()
+	p := setupGlobalTest(t,
+		WithCustomTraceExporter(exp),
+		WithCustomMetricReader(reader),
+		WithSyncSpanProcessor(),
+		WithExporterMetrics(),
+	)
+	checkExportCounts(t, p, exp, reader)
+}
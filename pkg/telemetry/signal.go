@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// shutdownTimeout bounds the final flush and shutdown run by RunWithSignals,
+// and the flushes triggered by InstallFlushSignal.
+const shutdownTimeout = 10 * time.Second
+
+// RunWithSignals blocks until the process receives SIGINT or SIGTERM, or ctx
+// is done, then calls shutdown once with a context bounded by a timeout and
+// returns its error. Pass a shutdown function that stops the application and
+// then flushes telemetry, so nothing is lost when Kubernetes terminates a pod.
+func RunWithSignals(ctx context.Context, shutdown func(context.Context) error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	// Stop receiving signal notifications as soon as possible, so a second
+	// signal terminates the process.
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return shutdown(shutdownCtx)
+}
+
+// InstallFlushSignal flushes the telemetry buffered by the pipeline registered
+// by the last Setup whenever the process receives sig, for example
+// syscall.SIGUSR1, without shutting it down. Flush errors are reported to the
+// global error handler. Call stop to stop listening for sig.
+func InstallFlushSignal(sig os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				flushCurrent()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// flushCurrent flushes the providers registered by the last Setup, if any.
+func flushCurrent() {
+	p := currentProviders.Load()
+	if p == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := p.ForceFlush(ctx); err != nil {
+		otel.Handle(err)
+	}
+}
@@ -0,0 +1,69 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// raise sends sig to the test process. The test must be notified of sig
+// itself, so that a signal nobody else listens for does not terminate it.
+func raise(t *testing.T, sig os.Signal) {
+	t.Helper()
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Signal(sig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunWithSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the process on Windows")
+	}
+	// ch is never stopped: a SIGTERM still being delivered when the test
+	// ends must not terminate the test binary.
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM)
+
+	var calls atomic.Int64
+	done := make(chan error)
+	go func() {
+		done <- RunWithSignals(context.Background(), func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("shutdown context has no deadline")
+			}
+			calls.Add(1)
+			return nil
+		})
+	}()
+
+	// RunWithSignals may not listen yet, so signal until it returns.
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	timeout := time.After(5 * time.Second)
+	for returned := false; !returned; {
+		raise(t, syscall.SIGTERM)
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("RunWithSignals = %v", err)
+			}
+			returned = true
+		case <-tick.C:
+		case <-timeout:
+			t.Fatal("RunWithSignals did not return after SIGTERM")
+		}
+	}
+	raise(t, syscall.SIGTERM)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("shutdown called %d times, want 1", got)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package telemetry
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+)
+
+func TestInstallFlushSignal(t *testing.T) {
+	// Only the flush can export before the test ends.
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "3600000")
+	// ch is never stopped, see TestRunWithSignals.
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+
+	exp := &fakeSpanExporter{}
+	p := setupGlobalTest(t, WithCustomTraceExporter(exp))
+	stop := InstallFlushSignal(syscall.SIGUSR1)
+	defer stop()
+
+	_, span := p.TracerProvider.Tracer("test").Start(context.Background(), "buffered")
+	span.End()
+	if got := len(exp.Spans()); got != 0 {
+		t.Fatalf("exported %d spans before the signal, want 0", got)
+	}
+	raise(t, syscall.SIGUSR1)
+	if !waitFor(func() bool { return len(exp.Spans()) == 1 }) {
+		t.Errorf("exported %d spans after SIGUSR1, want 1", len(exp.Spans()))
+	}
+}
@@ -0,0 +1,61 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartSpan starts a span using the tracer scoped to the configured service
+// name. It is shorthand for telemetry.Tracer("").Start(ctx, name, opts...).
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer("").Start(ctx, name, opts...)
+}
+
+// StartSpanWithLinks is like StartSpan but links the span to links, for
+// example to the traces of each message of a batch processed in one span:
+//
+//	links := make([]trace.Link, 0, len(msgs))
+//	for _, msg := range msgs {
+//		links = append(links, telemetry.LinkFromCarrier(msg.Headers))
+//	}
+//	ctx, span := telemetry.StartSpanWithLinks(ctx, "process batch", links...)
+func StartSpanWithLinks(ctx context.Context, name string, links ...trace.Link) (context.Context, trace.Span) {
+	return StartSpan(ctx, name, trace.WithLinks(links...))
+}
+
+// LinkFromCarrier returns a link to the span context extracted from carrier,
+// such as the headers of a queue message, with the global propagator. When
+// carrier holds no span context the link is invalid and spans ignore it.
+func LinkFromCarrier(carrier map[string]string) trace.Link {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(carrier))
+	return trace.LinkFromContext(ctx)
+}
+
+// RecordError records err as an exception event on span and sets the span
+// status to Error. It does nothing when err is nil.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// EndSpanWithError ends span after recording the error err points to, if any.
+// It is meant to be deferred with a named error result:
+//
+//	func work(ctx context.Context) (err error) {
+//		ctx, span := tracer.Start(ctx, "work")
+//		defer telemetry.EndSpanWithError(span, &err)
+//		...
+//	}
+func EndSpanWithError(span trace.Span, err *error) {
+	if err != nil {
+		RecordError(span, *err)
+	}
+	span.End()
+}
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEndSpanWithError(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	p := setupTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+	tracer := p.TracerProvider.Tracer("test")
+
+	work := func(fail error) (err error) {
+		_, span := tracer.Start(context.Background(), "work")
+		defer EndSpanWithError(span, &err)
+		return fail
+	}
+	work(errors.New("disk full"))
+	work(nil)
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	failed, ok := spans[0], spans[1]
+	if got := failed.Status(); got.Code != codes.Error || got.Description != "disk full" {
+		t.Errorf("failed span status = %+v, want Error: disk full", got)
+	}
+	if events := failed.Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("failed span events = %v, want one exception", events)
+	} else if !hasAttribute(events[0], "exception.message", "disk full") {
+		t.Errorf("exception attributes = %v, want exception.message disk full", events[0].Attributes)
+	}
+	if got := ok.Status().Code; got != codes.Unset {
+		t.Errorf("successful span status = %v, want Unset", got)
+	}
+	if len(ok.Events()) != 0 {
+		t.Errorf("successful span events = %v, want none", ok.Events())
+	}
+}
+
+func hasAttribute(event sdktrace.Event, key, value string) bool {
+	for _, kv := range event.Attributes {
+		if string(kv.Key) == key && kv.Value.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStartSpan(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+
+	_, span := StartSpan(context.Background(), "checkout")
+	if !span.SpanContext().IsValid() {
+		t.Error("span context is not valid")
+	}
+	span.End()
+	if spans := exp.Spans(); len(spans) != 1 || spans[0].Name() != "checkout" {
+		t.Errorf("exported %v, want the checkout span", spans)
+	}
+}
+
+func TestStartSpanWithLinks(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+
+	var links []trace.Link
+	var want []trace.SpanContext
+	for range 2 {
+		ctx, span := StartSpan(context.Background(), "publish")
+		headers := map[string]string{}
+		InjectToMap(ctx, headers)
+		span.End()
+		links = append(links, LinkFromCarrier(headers))
+		want = append(want, span.SpanContext())
+	}
+	// A message without trace context yields an invalid link, which is
+	// dropped.
+	links = append(links, LinkFromCarrier(map[string]string{}))
+
+	_, span := StartSpanWithLinks(context.Background(), "process batch", links...)
+	span.End()
+
+	spans := exp.Spans()
+	got := spans[len(spans)-1].Links()
+	if len(got) != len(want) {
+		t.Fatalf("span has %d links, want %d", len(got), len(want))
+	}
+	for i, l := range got {
+		if !l.SpanContext.Equal(want[i].WithRemote(true)) {
+			t.Errorf("link %d = %v, want %v", i, l.SpanContext, want[i])
+		}
+	}
+}
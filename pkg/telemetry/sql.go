@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// OpenDB is like sql.Open but instruments the connection pool so every query,
+// exec and transaction produces a span carrying db.system, set to dbSystem
+// (such as "postgresql" or "sqlite"), and the statement as db.statement.
+// Spans use the global TracerProvider, so OpenDB may be called before Setup.
+//
+// An existing *sql.DB cannot be wrapped because its driver and DSN are not
+// recoverable; open it with OpenDB, or OpenDBConnector, instead.
+func OpenDB(driverName, dataSourceName, dbSystem string) (*sql.DB, error) {
+	return otelsql.Open(driverName, dataSourceName, sqlOptions(dbSystem)...)
+}
+
+// OpenDBConnector is like sql.OpenDB with the instrumentation of OpenDB, for
+// drivers configured through a driver.Connector.
+func OpenDBConnector(c driver.Connector, dbSystem string) *sql.DB {
+	return otelsql.OpenDB(c, sqlOptions(dbSystem)...)
+}
+
+func sqlOptions(dbSystem string) []otelsql.Option {
+	return []otelsql.Option{
+		otelsql.WithAttributes(semconv.DBSystemKey.String(dbSystem)),
+	}
+}
@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// fakeConnector opens connections whose statements return no rows.
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"id"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+func TestOpenDBConnector(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+
+	db := OpenDBConnector(fakeConnector{}, "sqlite")
+	defer db.Close()
+	rows, err := db.QueryContext(context.Background(), "SELECT id FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	var query bool
+	for _, s := range exp.Spans() {
+		attrs := attribute.NewSet(s.Attributes()...)
+		if v, _ := attrs.Value("db.system"); v.AsString() != "sqlite" {
+			t.Errorf("span %q attributes = %v, want db.system sqlite", s.Name(), s.Attributes())
+		}
+		v, _ := attrs.Value("db.statement")
+		query = query || v.AsString() == "SELECT id FROM users"
+	}
+	if !query {
+		t.Errorf("no span carries the query statement among %d spans", len(exp.Spans()))
+	}
+}
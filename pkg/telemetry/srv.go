@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// lookupSRV is a variable so that DNS can be faked.
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// resolveSRV returns the host:port of the preferred target of the SRV record
+// named service, such as _otlp._tcp.collector.example.com. The resolver
+// orders targets by priority and shuffles them by weight, so the first target
+// is picked.
+func resolveSRV(ctx context.Context, service string) (string, error) {
+	_, addrs, err := lookupSRV(ctx, "", "", service)
+	if err != nil {
+		return "", fmt.Errorf("telemetry: resolving SRV record %s: %w", service, err)
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("telemetry: SRV record %s has no targets", service)
+	}
+	host := strings.TrimSuffix(addrs[0].Target, ".")
+	return net.JoinHostPort(host, strconv.Itoa(int(addrs[0].Port))), nil
+}
+
+// applySRVEndpoint sets the collector address from the SRV record set with
+// WithSRVEndpoint, if any.
+func (c *config) applySRVEndpoint(ctx context.Context) error {
+	if c.srvService == "" {
+		return nil
+	}
+	addr, err := resolveSRV(ctx, c.srvService)
+	if err != nil {
+		return err
+	}
+	c.otlpAddress = addr
+	return nil
+}
+
+// dialSRV returns a gRPC dialer that resolves the SRV record named service
+// again on every connection attempt, so a reconnect reaches the current
+// target. It falls back to addr, resolved at startup, when DNS fails.
+func dialSRV(service string) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		if target, err := resolveSRV(ctx, service); err == nil {
+			addr = target
+		}
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	}
+}
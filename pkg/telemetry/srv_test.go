@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// fakeSRV makes lookupSRV return addrs for every name until the test ends.
+func fakeSRV(t *testing.T, addrs ...*net.SRV) {
+	t.Helper()
+	prev := lookupSRV
+	lookupSRV = func(context.Context, string, string, string) (string, []*net.SRV, error) {
+		return "", addrs, nil
+	}
+	t.Cleanup(func() { lookupSRV = prev })
+}
+
+func TestResolveSRV(t *testing.T) {
+	fakeSRV(t,
+		&net.SRV{Target: "collector-1.example.com.", Port: 4317, Priority: 10},
+		&net.SRV{Target: "collector-2.example.com.", Port: 4317, Priority: 20},
+	)
+	got, err := resolveSRV(context.Background(), "_otlp._tcp.example.com")
+	if err != nil || got != "collector-1.example.com:4317" {
+		t.Errorf("resolveSRV = %q, %v, want collector-1.example.com:4317", got, err)
+	}
+
+	fakeSRV(t)
+	if _, err := resolveSRV(context.Background(), "_otlp._tcp.example.com"); err == nil {
+		t.Error("resolveSRV succeeded without targets")
+	}
+}
+
+func TestWithSRVEndpoint(t *testing.T) {
+	c := newCollector(t)
+	host, port, err := net.SplitHostPort(c.address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNum, _ := strconv.Atoi(port)
+	fakeSRV(t, &net.SRV{Target: host + ".", Port: uint16(portNum)})
+
+	p, err := Setup(context.Background(), HttpExporter, "", resource.Empty(),
+		WithoutGlobalRegistration(),
+		WithSyncSpanProcessor(),
+		WithInsecure(true),
+		WithSRVEndpoint("_otlp._tcp.collector.example.com"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportAll(t, p)
+	if got := len(c.headers("/v1/traces", "Content-Type")); got == 0 {
+		t.Error("no spans reached the collector named by the SRV record")
+	}
+}
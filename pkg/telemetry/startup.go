@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// processStart is the time the process started. Where the operating system
+// start time cannot be read, it is the time this package was initialized,
+// which happens before main runs.
+var processStart = readProcessStart(time.Now())
+
+// startupDurationKey holds the seconds from process start until the pipeline
+// was set up.
+const startupDurationKey = attribute.Key("otelcore.startup.duration")
+
+// startupResourceKeys are the resource attributes copied to the startup span,
+// so deployments can be found by span attributes alone.
+var startupResourceKeys = []attribute.Key{
+	semconv.ServiceNameKey,
+	semconv.ServiceVersionKey,
+	semconv.DeploymentEnvironmentKey,
+	"vcs.revision",
+}
+
+// exporterNames are the values of the otelcore.exporter attribute.
+var exporterNames = map[ExporterType]string{
+	GrpcExporter:   "otlp_grpc",
+	HttpExporter:   "otlp_http",
+	StdoutExporter: "stdout",
+	NoneExporter:   "none",
+	ZipkinExporter: "zipkin",
+}
+
+// recordStartupSpan records the span enabled by WithStartupSpan with the
+// tracer provider of p. It does nothing when traces are disabled.
+func recordStartupSpan(ctx context.Context, cfg *config, p *Providers) {
+	if p.TracerProvider == nil {
+		return
+	}
+	end := time.Now()
+	attrs := []attribute.KeyValue{
+		attribute.String("otelcore.exporter", exporterNames[cfg.exporterType]),
+		attribute.String("otelcore.endpoint", cfg.otlpAddress),
+		startupDurationKey.Float64(end.Sub(processStart).Seconds()),
+	}
+	if cfg.resources != nil {
+		set := cfg.resources.Set()
+		for _, key := range startupResourceKeys {
+			if v, ok := set.Value(key); ok {
+				attrs = append(attrs, attribute.KeyValue{Key: key, Value: v})
+			}
+		}
+	}
+	_, span := p.TracerProvider.Tracer(name).Start(ctx, "service.startup",
+		trace.WithNewRoot(),
+		trace.WithTimestamp(processStart),
+		trace.WithAttributes(attrs...),
+	)
+	span.End(trace.WithTimestamp(end))
+}
@@ -0,0 +1,72 @@
+package telemetry
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+// clockTicks is the number of clock ticks per second in which /proc reports
+// the process start time. Linux fixes it to 100 for user space on every
+// architecture Go supports.
+const clockTicks = 100
+
+// readProcessStart returns the start time of the process, read from
+// /proc/self/stat and the boot time in /proc/stat, or fallback if either
+// cannot be read.
+func readProcessStart(fallback time.Time) time.Time {
+	stat, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return fallback
+	}
+	ticks, err := parseStartTicks(stat)
+	if err != nil {
+		return fallback
+	}
+	sys, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return fallback
+	}
+	boot, err := parseBootTime(sys)
+	if err != nil {
+		return fallback
+	}
+	start := time.Unix(boot, 0).Add(time.Duration(ticks) * time.Second / clockTicks)
+	// The boot time has a resolution of a second, so the start time can be
+	// off by up to a second; never report it after the package was
+	// initialized.
+	if start.After(fallback) {
+		return fallback
+	}
+	return start
+}
+
+// parseStartTicks returns the starttime field of /proc/self/stat, in clock
+// ticks after boot.
+func parseStartTicks(stat []byte) (uint64, error) {
+	// The command name in parentheses may contain spaces and parentheses,
+	// so the fields are counted from the last closing parenthesis. The
+	// state is the third field and starttime the 22nd.
+	i := bytes.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0, errors.New("telemetry: malformed /proc/self/stat")
+	}
+	fields := bytes.Fields(stat[i+1:])
+	if len(fields) < 20 {
+		return 0, errors.New("telemetry: malformed /proc/self/stat")
+	}
+	return strconv.ParseUint(string(fields[19]), 10, 64)
+}
+
+// parseBootTime returns the btime line of /proc/stat, in seconds since the
+// Unix epoch.
+func parseBootTime(stat []byte) (int64, error) {
+	for _, line := range bytes.Split(stat, []byte("\n")) {
+		if v, ok := bytes.CutPrefix(line, []byte("btime ")); ok {
+			return strconv.ParseInt(string(bytes.TrimSpace(v)), 10, 64)
+		}
+	}
+	return 0, errors.New("telemetry: no btime in /proc/stat")
+}
@@ -0,0 +1,36 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStartTicks(t *testing.T) {
+	// The command name contains spaces and a closing parenthesis.
+	stat := []byte("4242 (my) app) S 1 4242 4242 0 -1 4194304 82 0 0 0 0 0 0 0 20 0 1 0 265169 2703360 272\n")
+	ticks, err := parseStartTicks(stat)
+	if err != nil || ticks != 265169 {
+		t.Errorf("parseStartTicks = %d, %v, want 265169", ticks, err)
+	}
+	if _, err := parseStartTicks([]byte("4242 (app) S 1")); err == nil {
+		t.Error("parseStartTicks accepted a truncated stat")
+	}
+}
+
+func TestParseBootTime(t *testing.T) {
+	boot, err := parseBootTime([]byte("cpu  1 2 3\nintr 5\nbtime 1792029608\nprocesses 7\n"))
+	if err != nil || boot != 1792029608 {
+		t.Errorf("parseBootTime = %d, %v, want 1792029608", boot, err)
+	}
+	if _, err := parseBootTime([]byte("cpu  1 2 3\n")); err == nil {
+		t.Error("parseBootTime accepted a stat without btime")
+	}
+}
+
+func TestReadProcessStart(t *testing.T) {
+	now := time.Now()
+	start := readProcessStart(now)
+	if start.After(now) || now.Sub(start) > time.Hour {
+		t.Errorf("process start %v is implausible at %v", start, now)
+	}
+}
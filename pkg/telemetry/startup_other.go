@@ -0,0 +1,11 @@
+//go:build !linux
+
+package telemetry
+
+import "time"
+
+// readProcessStart returns fallback: the process start time is only read on
+// Linux.
+func readProcessStart(fallback time.Time) time.Time {
+	return fallback
+}
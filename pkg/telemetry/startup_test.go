@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// testInit is taken after processStart, when the test binary initializes.
+var testInit = time.Now()
+
+func TestStartupSpan(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupTest(t,
+		WithCustomTraceExporter(exp),
+		WithSyncSpanProcessor(),
+		WithStartupSpan(),
+	)
+
+	spans := exp.Spans()
+	if len(spans) != 1 || spans[0].Name() != "service.startup" {
+		t.Fatalf("exported %v, want the service.startup span", spans)
+	}
+	s := spans[0]
+	if !s.StartTime().Equal(processStart) || s.StartTime().After(testInit) {
+		t.Errorf("span starts at %v, want the process start %v before %v", s.StartTime(), processStart, testInit)
+	}
+	var duration attribute.Value
+	for _, kv := range s.Attributes() {
+		if kv.Key == startupDurationKey {
+			duration = kv.Value
+		}
+	}
+	if want := s.EndTime().Sub(s.StartTime()).Seconds(); duration.AsFloat64() != want {
+		t.Errorf("%s = %v, want %v", startupDurationKey, duration.Emit(), want)
+	}
+}
@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// HistogramBuckets returns a View aggregating the histogram named
+// instrumentName into explicit buckets with the given boundaries, for example
+// to match latency SLOs. Pass it to WithView.
+func HistogramBuckets(instrumentName string, boundaries []float64) sdkmetric.View {
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Name: instrumentName, Kind: sdkmetric.InstrumentKindHistogram},
+		sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: boundaries}},
+	)
+}
+
+// DropAttributes returns a View removing the attributes named by keys from the
+// instruments matching instrumentName before they are aggregated, for example
+// to strip a high-cardinality user_id. instrumentName may contain the "*" and
+// "?" wildcards. Pass it to WithView.
+func DropAttributes(instrumentName string, keys ...string) sdkmetric.View {
+	deny := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		deny[i] = attribute.Key(k)
+	}
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Name: instrumentName},
+		sdkmetric.Stream{AttributeFilter: attribute.NewDenyKeysFilter(deny...)},
+	)
+}
@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectMetric returns the data of the metric named name read by reader.
+func collectMetric(t *testing.T, reader sdkmetric.Reader, name string) metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	t.Fatalf("no metric %s", name)
+	return nil
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	boundaries := []float64{100, 250, 500}
+	p := setupTest(t,
+		WithCustomMetricReader(reader),
+		WithView(HistogramBuckets("latency", boundaries)),
+	)
+	hist, err := p.MeterProvider.Meter("test").Float64Histogram("latency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []float64{50, 120, 200, 499, 1000} {
+		hist.Record(context.Background(), v)
+	}
+
+	dp := collectMetric(t, reader, "latency").(metricdata.Histogram[float64]).DataPoints[0]
+	if !slices.Equal(dp.Bounds, boundaries) {
+		t.Errorf("bounds = %v, want %v", dp.Bounds, boundaries)
+	}
+	if want := []uint64{1, 2, 1, 1}; !slices.Equal(dp.BucketCounts, want) {
+		t.Errorf("bucket counts = %v, want %v", dp.BucketCounts, want)
+	}
+}
+
+func TestDropAttributes(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p := setupTest(t,
+		WithCustomMetricReader(reader),
+		WithView(DropAttributes("http.*", "user_id")),
+	)
+	counter, err := p.MeterProvider.Meter("test").Int64Counter("http.requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []string{"alice", "bob"} {
+		counter.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("user_id", user),
+			attribute.String("route", "/users"),
+		))
+	}
+
+	dps := collectMetric(t, reader, "http.requests").(metricdata.Sum[int64]).DataPoints
+	if len(dps) != 1 {
+		t.Fatalf("got %d data points, want the user_id ones merged into 1", len(dps))
+	}
+	if _, ok := dps[0].Attributes.Value("user_id"); ok {
+		t.Error("user_id was not dropped")
+	}
+	if v, _ := dps[0].Attributes.Value("route"); v.AsString() != "/users" {
+		t.Errorf("route = %q, want /users", v.AsString())
+	}
+	if dps[0].Value != 2 {
+		t.Errorf("value = %d, want 2", dps[0].Value)
+	}
+}
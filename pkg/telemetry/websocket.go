@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// isWebSocketUpgrade reports whether r asks to upgrade the connection to a
+// WebSocket.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// upgradeResponseWriter calls onUpgrade once when the handler takes over the
+// connection, which WebSocket libraries do to upgrade it.
+type upgradeResponseWriter struct {
+	http.ResponseWriter
+	onUpgrade func()
+	once      sync.Once
+}
+
+func (w *upgradeResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.once.Do(w.onUpgrade)
+	}
+	return conn, rw, err
+}
+
+func (w *upgradeResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer.
+func (w *upgradeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,90 @@
+package telemetry
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// upgrade sends a WebSocket upgrade request to srv and waits for the
+// 101 Switching Protocols response.
+func upgrade(t *testing.T, srv *httptest.Server) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	req := "GET /ws HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	status, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || !strings.Contains(status, "101") {
+		t.Fatalf("upgrade response %q, %v, want 101 Switching Protocols", status, err)
+	}
+	return conn
+}
+
+// webSocketServer serves /ws by hijacking the connection and holding it
+// until the test ends, like a WebSocket library would.
+func webSocketServer(t *testing.T, opts ...HTTPHandlerOption) *httptest.Server {
+	t.Helper()
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		rw.Flush()
+		<-release
+	})
+	srv := httptest.NewServer(NewHTTPHandler(mux, opts...))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+	return srv
+}
+
+func TestWebSocketSpanEndsAtUpgrade(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+	upgrade(t, webSocketServer(t))
+
+	// The connection is still open, but its span has ended.
+	if !waitFor(func() bool { return len(exp.Spans()) == 1 }) {
+		t.Fatalf("exported %d spans while the connection is open, want 1", len(exp.Spans()))
+	}
+	span := exp.Spans()[0]
+	if span.Name() != "/ws" {
+		t.Errorf("span name = %q, want /ws", span.Name())
+	}
+	if events := span.Events(); len(events) != 1 || events[0].Name != "websocket.upgrade" {
+		t.Errorf("span events = %v, want websocket.upgrade", events)
+	}
+}
+
+func TestWebSocketSpanSkipped(t *testing.T) {
+	exp := &fakeSpanExporter{}
+	setupGlobalTest(t, WithCustomTraceExporter(exp), WithSyncSpanProcessor())
+	srv := webSocketServer(t, WithWebSocketSpans(WebSocketSpanSkipped))
+	upgrade(t, srv)
+
+	// A plain request is still traced.
+	resp, err := http.Get(srv.URL + "/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	waitFor(func() bool { return len(exp.Spans()) > 0 })
+	spans := exp.Spans()
+	if len(spans) != 1 || spans[0].Name() == "/ws" {
+		t.Errorf("exported spans %v, want only the plain request", spans)
+	}
+}
@@ -0,0 +1,17 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/contrib/bridges/otelzap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewZapCore returns a zapcore.Core that emits zap entries as OpenTelemetry log
+// records through the global LoggerProvider. An empty name uses the configured
+// service name as instrumentation scope.
+//
+// To correlate an entry with the active span, pass the request context as a
+// field, for example zap.Any("context", ctx). Combine the core with an existing
+// one using zapcore.NewTee to keep writing to the console as well.
+func NewZapCore(name string) zapcore.Core {
+	return otelzap.NewCore(scopeName(name))
+}
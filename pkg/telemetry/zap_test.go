@@ -0,0 +1,28 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestZapCoreCorrelatesSpan(t *testing.T) {
+	exp := &memLogExporter{}
+	p := setupGlobalTest(t, WithCustomLogExporter(exp), WithSyncSpanProcessor())
+
+	ctx, span := p.TracerProvider.Tracer("test").Start(context.Background(), "request")
+	zap.New(NewZapCore("test")).Info("handled", zap.Any("context", ctx))
+	span.End()
+
+	records := exp.Records()
+	if len(records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(records))
+	}
+	if got, want := records[0].TraceID(), span.SpanContext().TraceID(); got != want {
+		t.Errorf("record trace ID = %s, want %s", got, want)
+	}
+	if got := records[0].Body().AsString(); got != "handled" {
+		t.Errorf("record body = %q, want handled", got)
+	}
+}